@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/OnsagerHe/geoip-detector/pkg/retriever"
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
 	pb "github.com/OnsagerHe/geoip-detector/proto/gen"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -27,11 +28,14 @@ func initConfig() *Config {
 type Frontend struct {
 	pb.UnimplementedApiServer
 	Retriever *retriever.Retriever
+	// profiles are the ones given by the flags, used when a request does not send any
+	profiles []utils.Profile
 }
 
 func InitServer(geoIP *retriever.Retriever) *Frontend {
 	return &Frontend{
 		Retriever: geoIP,
+		profiles:  geoIP.Process.Profiles,
 	}
 }
 
@@ -2,23 +2,57 @@ package api
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/OnsagerHe/geoip-detector/pkg/utils"
 	pb "github.com/OnsagerHe/geoip-detector/proto/gen"
 )
 
-func (r *Frontend) PutEndpoint(ctx context.Context, req *pb.PutEndpointRequest) (*[]pb.PutEndpointResponse, error) {
+func (r *Frontend) PutEndpoint(ctx context.Context, req *pb.PutEndpointRequest) (*pb.PutEndpointResponse, error) {
 	r.Retriever.Process.Logger.Debug("call CheckStatus functions!")
 
 	if req.GetLoop() != 0 {
 		r.Retriever.Utils.Loop = uint8(req.Loop)
 	}
+	profiles, err := r.profilesFromRequest(req.GetProfiles())
+	if err != nil {
+		return nil, fmt.Errorf("invalid profiles: %w", err)
+	}
+	r.Retriever.Process.Profiles = profiles
 	r.Retriever.Process.Resource = utils.EndpointMetadata{Endpoint: req.Endpoint}
 
 	r.Retriever.Process.Logger.Debug("value for endpoint and loop:" + req.Endpoint)
-	res, err := r.Retriever.CheckEndpoint()
+	res, err := r.Retriever.CheckEndpoint(ctx)
 	if err != nil {
 		return res, err
 	}
 	return res, nil
 }
+
+// profilesFromRequest converts the profiles of the request, the flag-configured ones are used when it sends none
+func (r *Frontend) profilesFromRequest(requested []*pb.RequestProfile) ([]utils.Profile, error) {
+	if len(requested) == 0 {
+		return r.profiles, nil
+	}
+
+	profiles := make([]utils.Profile, 0, len(requested))
+	for i, p := range requested {
+		profile := utils.Profile{
+			Name:           p.GetName(),
+			Method:         p.GetMethod(),
+			Headers:        p.GetHeaders(),
+			UserAgent:      p.GetUserAgent(),
+			AcceptLanguage: p.GetAcceptLanguage(),
+			Cookies:        p.GetCookies(),
+			Body:           p.GetBody(),
+		}
+		if profile.Name == "" {
+			profile.Name = fmt.Sprintf("profile-%d", i)
+		}
+		profiles = append(profiles, profile)
+	}
+	if err := utils.ValidateProfiles(profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
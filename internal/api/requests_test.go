@@ -0,0 +1,55 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+	pb "github.com/OnsagerHe/geoip-detector/proto/gen"
+)
+
+func TestProfilesFromRequest(t *testing.T) {
+	configured := []utils.Profile{{Name: "configured", Method: "GET"}}
+	frontend := &Frontend{profiles: configured}
+
+	tests := []struct {
+		name     string
+		input    []*pb.RequestProfile
+		expected []utils.Profile
+		hasError bool
+	}{
+		{
+			name:     "No profile falls back to the configured ones",
+			input:    nil,
+			expected: configured,
+		},
+		{
+			name: "Converted with defaults",
+			input: []*pb.RequestProfile{
+				{Name: "mobile", UserAgent: "Mobile", Headers: map[string]string{"X-Test": "1"}, Cookies: map[string]string{"consent": "yes"}},
+				{Method: "POST", Body: "{}", AcceptLanguage: "fr-FR"},
+			},
+			expected: []utils.Profile{
+				{Name: "mobile", Method: "GET", UserAgent: "Mobile", Headers: map[string]string{"X-Test": "1"}, Cookies: map[string]string{"consent": "yes"}},
+				{Name: "profile-1", Method: "POST", Body: "{}", AcceptLanguage: "fr-FR"},
+			},
+		},
+		{
+			name:     "Duplicate name",
+			input:    []*pb.RequestProfile{{Name: "mobile"}, {Name: "mobile"}},
+			hasError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := frontend.profilesFromRequest(tt.input)
+			if (err != nil) != tt.hasError {
+				t.Fatalf("profilesFromRequest() error = %v, expected error = %v", err, tt.hasError)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("profilesFromRequest() got = %+v, expected = %+v", got, tt.expected)
+			}
+		})
+	}
+}
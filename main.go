@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/OnsagerHe/geoip-detector/internal/api"
 
+	"github.com/OnsagerHe/geoip-detector/pkg/emulation"
 	"github.com/OnsagerHe/geoip-detector/pkg/retriever"
 	"github.com/OnsagerHe/geoip-detector/pkg/utils"
 	"github.com/OnsagerHe/geoip-detector/pkg/utils/logger"
@@ -27,14 +29,60 @@ func init() {
 	utils.FolderPath = flag.String("folder", "downloads", "path folder for images webpage")
 	utils.BrowserPath = flag.String("browser", "", "path to binary browser")
 	utils.Prd = flag.Bool("prod", true, "don't print debug log") // set var env\
+	utils.DNSSamples = flag.Uint("dns-samples", 5, "number of queries sent to each nameserver per country")
+	utils.Resolver = flag.String("resolver", "", "resolver checked for DNS tampering (default: first nameserver in /etc/resolv.conf)")
+	utils.SinkholesPath = flag.String("sinkholes", "", "path to a file listing known sinkhole IPs or CIDRs, one per line")
+	utils.CDNPrefixesPath = flag.String("cdn-prefixes", "config/cdn-prefixes.json", "path to the JSON list of IP ranges per CDN provider")
+	utils.MaxRedirects = flag.Uint("max-redirects", 10, "maximum number of redirects followed per request")
+	utils.ProfilesPath = flag.String("profiles", "", "path to a JSON list of request profiles (default: a bare GET)")
+	utils.NormalizationPath = flag.String("normalization", "", "path to the JSON normalization rules applied before comparing hashes")
+	utils.Protocols = flag.String("protocols", "", "comma separated protocols forced on every destination: auto, h1, h2 (default: auto)")
+	utils.CrawlDepth = flag.Uint("crawl-depth", 0, "follow same-site links up to this depth from each country, 0 disables the crawl")
+	utils.CrawlPages = flag.Uint("crawl-pages", 20, "maximum number of pages crawled from each country")
+	utils.Subresources = flag.Uint("subresources", 0, "maximum number of scripts, stylesheets, images and frames fetched and hashed per response, 0 disables it")
+	utils.SignaturesPath = flag.String("signatures", "config/signatures.json", "path to the JSON signatures of block, challenge and captcha pages")
+	utils.RequestTimeout = flag.Duration("request-timeout", 30*time.Second, "deadline of each request, redirects and body included")
+	utils.MaxBody = flag.Int64("max-body", 10<<20, "maximum body size kept in memory in bytes, larger bodies are still hashed in full")
+	utils.Retries = flag.Uint("retries", 2, "retries with backoff on transient network errors")
+	utils.Probes = flag.String("probes", "", "comma separated probes sent to every destination: sni-host-mismatch, no-sni, decoy-sni, ech")
+	utils.DecoySNI = flag.String("decoy-sni", "example.com", "server name used by the decoy probes")
+	utils.ScenarioPath = flag.String("scenario", "", "path to a JSON browser scenario (navigate, click, type, wait, assert, screenshot) run from each country")
+	utils.BrowserWorkers = flag.Uint("browser-workers", 4, "maximum number of concurrent screenshots and scenarios, they share one browser")
+	utils.Emulate = flag.String("emulate", "country", "browser timezone, locale and geolocation: country, none, a country code, or mismatches such as timezone=jp,geolocation=us")
+	utils.VisualThreshold = flag.Float64("visual-threshold", 1, "percentage of differing pixels above which a screenshot is flagged")
+	utils.SimilarityThreshold = flag.Float64("similarity-threshold", 0.9, "similarity above which two regional pages are near-identical (0 to 1)")
 	server = flag.Bool("server", true, "run api server")
 	flag.Parse()
 }
 
 func initGeoIP() *utils.GeoIP {
+	profiles, err := utils.LoadProfiles(*utils.ProfilesPath)
+	if err != nil {
+		log.Fatalf("Cannot load request profiles: %v\n", err)
+	}
+
+	protocols, err := utils.ParseProtocols(*utils.Protocols)
+	if err != nil {
+		log.Fatalf("Cannot parse protocols: %v\n", err)
+	}
+
+	probes, err := utils.ParseProbes(*utils.Probes)
+	if err != nil {
+		log.Fatalf("Cannot parse probes: %v\n", err)
+	}
+
+	mode, err := emulation.ParseMode(*utils.Emulate)
+	if err != nil {
+		log.Fatalf("Cannot parse emulation: %v\n", err)
+	}
+
 	res := &utils.GeoIP{
 		Resource:    utils.EndpointMetadata{Endpoint: *endpoint},
 		Analyzes:    nil,
+		Profiles:    profiles,
+		Protocols:   protocols,
+		Probes:      probes,
+		Emulation:   mode,
 		VPNProvider: vpn.Mullvad{},
 		Logger:      logger.CreateLogger(*utils.Prd),
 	}
@@ -61,7 +109,7 @@ func run() {
 	}
 
 	rtr.Process.Logger.Debug("value for endpoint and loop:" + *endpoint)
-	err := rtr.CheckEndpoint()
+	_, err := rtr.CheckEndpoint(context.Background())
 	if err != nil {
 		log.Fatal(err)
 	}
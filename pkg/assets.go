@@ -0,0 +1,90 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+// CompareAssets reports the subresources only some countries receive
+// and the ones whose content differs between countries
+func CompareAssets(data []utils.Analyze) []string {
+	// asset URL -> status and hash -> countries
+	assets := make(map[string]map[string]map[string]bool)
+	countries := make(map[string]bool)
+	for _, entry := range data {
+		if len(entry.Assets) == 0 || entry.IsProbe() {
+			continue
+		}
+		countries[entry.CountryCode] = true
+		for _, asset := range entry.Assets {
+			if assets[asset.URL] == nil {
+				assets[asset.URL] = make(map[string]map[string]bool)
+			}
+			key := fmt.Sprintf("%d %x", asset.StatusCode, asset.Hash)
+			if asset.Error != "" {
+				key = "error"
+			}
+			if assets[asset.URL][key] == nil {
+				assets[asset.URL][key] = make(map[string]bool)
+			}
+			assets[asset.URL][key][entry.CountryCode] = true
+		}
+	}
+	if len(countries) < 2 {
+		return nil
+	}
+
+	urls := make([]string, 0, len(assets))
+	for u := range assets {
+		urls = append(urls, u)
+	}
+	sort.Strings(urls)
+
+	var notes []string
+	for _, u := range urls {
+		variants := make(map[string][]string)
+		receiving := make(map[string]bool)
+		for key, codes := range assets[u] {
+			for code := range codes {
+				variants[key] = append(variants[key], code)
+				receiving[code] = true
+			}
+			sort.Strings(variants[key])
+		}
+
+		if len(receiving) < len(countries) {
+			var with, without []string
+			for code := range countries {
+				if receiving[code] {
+					with = append(with, code)
+				} else {
+					without = append(without, code)
+				}
+			}
+			sort.Strings(with)
+			sort.Strings(without)
+			notes = append(notes, fmt.Sprintf("%s only loaded in %s, not in %s",
+				u, strings.Join(with, ", "), strings.Join(without, ", ")))
+		}
+		if len(variants) > 1 {
+			notes = append(notes, fmt.Sprintf("%s differs: %s", u, describeVariants(variants)))
+		}
+	}
+
+	return notes
+}
+
+func DisplayAssets(notes []string) {
+	if len(notes) == 0 {
+		return
+	}
+
+	fmt.Println("Subresources:")
+	for _, note := range notes {
+		fmt.Printf("\t%s\n", note)
+	}
+	fmt.Println("")
+}
@@ -0,0 +1,85 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+func TestCompareAssets(t *testing.T) {
+	const script = "https://example.com/app.js"
+	const tracker = "https://tracker.example.net/t.js"
+	withAssets := func(country string, assets ...utils.Asset) utils.Analyze {
+		return utils.Analyze{CountryCode: country, Assets: assets}
+	}
+	asset := func(url string, hash byte) utils.Asset {
+		return utils.Asset{URL: url, StatusCode: 200, Hash: []byte{hash}}
+	}
+
+	tests := []struct {
+		name     string
+		data     []utils.Analyze
+		expected []string
+	}{
+		{
+			name: "Same assets everywhere",
+			data: []utils.Analyze{
+				withAssets("FR", asset(script, 0xaa)),
+				withAssets("DE", asset(script, 0xaa)),
+			},
+			expected: nil,
+		},
+		{
+			name: "Single country",
+			data: []utils.Analyze{
+				withAssets("FR", asset(script, 0xaa), asset(tracker, 0xbb)),
+			},
+			expected: nil,
+		},
+		{
+			name: "Asset only some countries receive",
+			data: []utils.Analyze{
+				withAssets("FR", asset(script, 0xaa), asset(tracker, 0xbb)),
+				withAssets("DE", asset(script, 0xaa), asset(tracker, 0xbb)),
+				withAssets("CN", asset(script, 0xaa)),
+			},
+			expected: []string{tracker + " only loaded in DE, FR, not in CN"},
+		},
+		{
+			name: "Same URL with different hashes",
+			data: []utils.Analyze{
+				withAssets("FR", asset(script, 0xaa)),
+				withAssets("DE", asset(script, 0xaa)),
+				withAssets("CN", asset(script, 0xcc)),
+			},
+			expected: []string{script + " differs: 200 aa from DE, FR; 200 cc from CN"},
+		},
+		{
+			name: "Failed asset",
+			data: []utils.Analyze{
+				withAssets("FR", asset(script, 0xaa)),
+				withAssets("CN", utils.Asset{URL: script, Error: "connection reset by peer"}),
+			},
+			expected: []string{script + " differs: 200 aa from FR; error from CN"},
+		},
+		{
+			name: "Probes skipped",
+			data: func() []utils.Analyze {
+				probe := withAssets("CN", asset(script, 0xcc))
+				probe.Probe = utils.ProbeNoSNI
+				return []utils.Analyze{withAssets("FR", asset(script, 0xaa)), withAssets("CN", asset(script, 0xaa)), probe}
+			}(),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompareAssets(tt.data)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("CompareAssets() got = %q, expected = %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package cdn
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+// Prefixes maps a provider name to the IP ranges it announces
+type Prefixes map[string][]netip.Prefix
+
+type headerSignature struct {
+	header   string
+	contains string
+}
+
+type cnameSuffix struct {
+	suffix   string
+	provider string
+}
+
+// cnameSuffixes is sorted from the longest suffix, the most specific one wins
+var cnameSuffixes = sortSuffixes(map[string][]string{
+	"akamai":     {".akamai.net.", ".akamaiedge.net.", ".akamaized.net.", ".edgekey.net.", ".edgesuite.net."},
+	"azure":      {".azureedge.net.", ".azurefd.net.", ".trafficmanager.net."},
+	"bunny":      {".b-cdn.net."},
+	"cloudflare": {".cdn.cloudflare.net.", ".cloudflare.net."},
+	"cloudfront": {".cloudfront.net."},
+	"fastly":     {".fastly.net.", ".fastlylb.net."},
+	"github":     {".github.io."},
+	"google":     {".googlehosted.com.", ".googleusercontent.com."},
+	"imperva":    {".incapdns.net."},
+	"netlify":    {".netlify.app.", ".netlify.com."},
+	"vercel":     {".vercel-dns.com.", ".vercel.app."},
+})
+
+func sortSuffixes(byProvider map[string][]string) []cnameSuffix {
+	var suffixes []cnameSuffix
+	for provider, list := range byProvider {
+		for _, suffix := range list {
+			suffixes = append(suffixes, cnameSuffix{suffix: suffix, provider: provider})
+		}
+	}
+	sort.Slice(suffixes, func(i, j int) bool {
+		if len(suffixes[i].suffix) != len(suffixes[j].suffix) {
+			return len(suffixes[i].suffix) > len(suffixes[j].suffix)
+		}
+		return suffixes[i].suffix < suffixes[j].suffix
+	})
+	return suffixes
+}
+
+var headerSignatures = map[string][]headerSignature{
+	"akamai":     {{"Server", "AkamaiGHost"}, {"X-Akamai-Transformed", ""}, {"Akamai-Grn", ""}},
+	"azure":      {{"X-Azure-Ref", ""}, {"X-MSEdge-Ref", ""}},
+	"bunny":      {{"Server", "BunnyCDN"}, {"CDN-RequestId", ""}},
+	"cloudflare": {{"CF-Ray", ""}, {"Server", "cloudflare"}},
+	"cloudfront": {{"X-Amz-Cf-Id", ""}, {"X-Amz-Cf-Pop", ""}, {"Via", "CloudFront"}},
+	"fastly":     {{"X-Fastly-Request-Id", ""}, {"X-Served-By", "cache-"}, {"Fastly-Debug-Digest", ""}},
+	"github":     {{"X-GitHub-Request-Id", ""}},
+	"google":     {{"Server", "Google Frontend"}, {"Server", "gws"}},
+	"imperva":    {{"X-Iinfo", ""}, {"X-CDN", "Imperva"}},
+	"netlify":    {{"X-NF-Request-Id", ""}, {"Server", "Netlify"}},
+	"vercel":     {{"X-Vercel-Id", ""}, {"Server", "Vercel"}},
+}
+
+// LoadPrefixes reads a JSON object mapping provider names to CIDR lists,
+// a missing file only disables the IP range evidence
+func LoadPrefixes(path string) (Prefixes, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		log.Printf("CDN prefix list %s not found, IP ranges are ignored\n", path)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string][]string
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+
+	prefixes := make(Prefixes)
+	for provider, cidrs := range raw {
+		for _, cidr := range cidrs {
+			prefix, err := netip.ParsePrefix(cidr)
+			if err != nil {
+				return nil, err
+			}
+			prefixes[provider] = append(prefixes[provider], prefix.Masked())
+		}
+	}
+
+	return prefixes, nil
+}
+
+// IdentifyAnalyzes sets the provider of every analyze
+func IdentifyAnalyzes(res *utils.GeoIP, analyzes []*utils.Analyze, prefixes Prefixes) {
+	for i := range analyzes {
+		analyzes[i].Provider = Identify(&res.Resource, analyzes[i], prefixes)
+	}
+}
+
+// Identify weighs the CNAME chain, the destination IP and the response headers,
+// the provider with the most evidence wins
+func Identify(resource *utils.EndpointMetadata, analyze *utils.Analyze, prefixes Prefixes) utils.Provider {
+	evidence := make(map[string][]string)
+
+	for _, cname := range resource.CnameChain {
+		if provider := matchCNAME(cname); provider != "" {
+			evidence[provider] = append(evidence[provider], "cname "+cname)
+		}
+	}
+
+	if addr, err := netip.ParseAddr(analyze.IpDest); err == nil {
+		for provider, ranges := range prefixes {
+			for _, prefix := range ranges {
+				if prefix.Contains(addr.Unmap()) {
+					evidence[provider] = append(evidence[provider], "prefix "+prefix.String())
+					break
+				}
+			}
+		}
+	}
+
+	for provider, header := range matchHeaders(analyze.Headers) {
+		evidence[provider] = append(evidence[provider], "header "+header)
+	}
+
+	var best utils.Provider
+	providers := make([]string, 0, len(evidence))
+	for provider := range evidence {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	for _, provider := range providers {
+		if len(evidence[provider]) > len(best.Evidence) {
+			best = utils.Provider{Name: provider, Evidence: evidence[provider]}
+		}
+	}
+
+	return best
+}
+
+func matchCNAME(cname string) string {
+	cname = strings.ToLower(cname)
+	if !strings.HasSuffix(cname, ".") {
+		cname += "."
+	}
+	for _, s := range cnameSuffixes {
+		if strings.HasSuffix(cname, s.suffix) {
+			return s.provider
+		}
+	}
+	return ""
+}
+
+func matchHeaders(headers http.Header) map[string]string {
+	matches := make(map[string]string)
+	for provider, signatures := range headerSignatures {
+		for _, sig := range signatures {
+			value := headers.Get(sig.header)
+			if value == "" {
+				continue
+			}
+			if sig.contains == "" || strings.Contains(strings.ToLower(value), strings.ToLower(sig.contains)) {
+				matches[provider] = sig.header
+				break
+			}
+		}
+	}
+	return matches
+}
@@ -0,0 +1,100 @@
+package cdn
+
+import (
+	"net/http"
+	"net/netip"
+	"path/filepath"
+	"testing"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+func TestMatchCNAME(t *testing.T) {
+	tests := []struct {
+		name     string
+		cname    string
+		expected string
+	}{
+		{name: "Akamai edge", cname: "www.example.com.edgekey.net.", expected: "akamai"},
+		{name: "Without trailing dot", cname: "d111111abcdef8.cloudfront.net", expected: "cloudfront"},
+		{name: "Upper case", cname: "EXAMPLE.CDN.CLOUDFLARE.NET.", expected: "cloudflare"},
+		{name: "Unknown", cname: "example.org.", expected: ""},
+		{name: "Suffix without dot boundary", cname: "notcloudfront.net.", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchCNAME(tt.cname); got != tt.expected {
+				t.Errorf("matchCNAME(%q) = %q, expected %q", tt.cname, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCNAMESuffixesOrder(t *testing.T) {
+	for i := 1; i < len(cnameSuffixes); i++ {
+		if len(cnameSuffixes[i].suffix) > len(cnameSuffixes[i-1].suffix) {
+			t.Fatalf("%s comes after the shorter %s", cnameSuffixes[i].suffix, cnameSuffixes[i-1].suffix)
+		}
+	}
+}
+
+func TestIdentify(t *testing.T) {
+	prefixes := Prefixes{
+		"cloudflare": {netip.MustParsePrefix("104.16.0.0/13")},
+		"fastly":     {netip.MustParsePrefix("151.101.0.0/16")},
+	}
+
+	tests := []struct {
+		name     string
+		chain    []string
+		ip       string
+		headers  http.Header
+		expected string
+	}{
+		{
+			name:     "Prefix and header",
+			ip:       "104.16.1.1",
+			headers:  http.Header{"Cf-Ray": {"8a1b2c3d4e5f-CDG"}},
+			expected: "cloudflare",
+		},
+		{
+			name:     "CNAME and prefix outweigh a header",
+			chain:    []string{"example.global.fastly.net."},
+			ip:       "151.101.1.1",
+			headers:  http.Header{"Server": {"cloudflare"}},
+			expected: "fastly",
+		},
+		{
+			name:     "No evidence",
+			ip:       "192.0.2.1",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := &utils.EndpointMetadata{CnameChain: tt.chain}
+			got := Identify(resource, &utils.Analyze{IpDest: tt.ip, Headers: tt.headers}, prefixes)
+			if got.Name != tt.expected {
+				t.Errorf("Identify() = %q %v, expected %q", got.Name, got.Evidence, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadPrefixes(t *testing.T) {
+	prefixes, err := LoadPrefixes(filepath.Join("..", "..", "config", "cdn-prefixes.json"))
+	if err != nil {
+		t.Fatalf("LoadPrefixes() error = %v", err)
+	}
+	for _, provider := range []string{"cloudflare", "cloudfront", "fastly"} {
+		if len(prefixes[provider]) == 0 {
+			t.Errorf("LoadPrefixes() has no range for %s", provider)
+		}
+	}
+
+	if prefixes, err := LoadPrefixes(filepath.Join(t.TempDir(), "missing.json")); prefixes != nil || err != nil {
+		t.Errorf("LoadPrefixes() of a missing file = %v, %v, expected nothing", prefixes, err)
+	}
+}
@@ -0,0 +1,85 @@
+package pkg
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+// CompareCertificates compares the leaf certificates served across countries and destination IPs,
+// regional certificates, expired edge nodes and chains that fail verification are reported
+func CompareCertificates(data []utils.Analyze) []string {
+	var notes []string
+	leafCount := make(map[string]int)
+	issuerCount := make(map[string]int)
+	total := 0
+
+	for _, entry := range data {
+		if entry.TLS == nil || len(entry.TLS.Chain) == 0 || entry.IsProbe() {
+			continue
+		}
+		leaf := entry.TLS.Chain[0]
+		leafCount[leaf.SPKIHash+leaf.Serial]++
+		issuerCount[leaf.Issuer]++
+		total++
+	}
+	if total == 0 {
+		return nil
+	}
+
+	mostLeaf, mostIssuer := mostFrequent(leafCount), mostFrequent(issuerCount)
+	now := time.Now()
+
+	for _, entry := range data {
+		if entry.TLS == nil || len(entry.TLS.Chain) == 0 || entry.IsProbe() {
+			continue
+		}
+		leaf := entry.TLS.Chain[0]
+		where := fmt.Sprintf("%s (%s)", entry.CountryCode, entry.IpDest)
+
+		if leaf.SPKIHash+leaf.Serial != mostLeaf {
+			notes = append(notes, fmt.Sprintf("%s serves a regional certificate: serial %s issued by %s",
+				where, leaf.Serial, leaf.Issuer))
+		}
+		if now.After(leaf.NotAfter) || now.Before(leaf.NotBefore) {
+			notes = append(notes, fmt.Sprintf("%s serves a certificate outside its validity (%s to %s)",
+				where, leaf.NotBefore.Format(time.DateOnly), leaf.NotAfter.Format(time.DateOnly)))
+		}
+		if entry.TLS.VerifyError != "" {
+			if leaf.Issuer != mostIssuer {
+				notes = append(notes, fmt.Sprintf("%s certificate does not verify and has an unusual issuer %s, interception suspected: %s",
+					where, leaf.Issuer, entry.TLS.VerifyError))
+			} else {
+				notes = append(notes, fmt.Sprintf("%s certificate does not verify: %s", where, entry.TLS.VerifyError))
+			}
+		}
+		if entry.TLS.OCSPStatus == utils.OCSPRevoked {
+			notes = append(notes, fmt.Sprintf("%s staples an OCSP response saying the certificate is revoked", where))
+		}
+	}
+
+	return notes
+}
+
+func mostFrequent(count map[string]int) string {
+	best, highest := "", 0
+	for key, n := range count {
+		if n > highest || (n == highest && key < best) {
+			best, highest = key, n
+		}
+	}
+	return best
+}
+
+func DisplayCertificates(notes []string) {
+	if len(notes) == 0 {
+		return
+	}
+
+	fmt.Println("Certificates:")
+	for _, note := range notes {
+		fmt.Printf("\t%s\n", note)
+	}
+	fmt.Println("")
+}
@@ -0,0 +1,112 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+func TestCompareCertificates(t *testing.T) {
+	valid := utils.Certificate{
+		Serial:    "1",
+		Issuer:    "CN=Public CA",
+		SPKIHash:  "aa",
+		NotBefore: time.Now().Add(-time.Hour),
+		NotAfter:  time.Now().Add(time.Hour),
+	}
+	regional := valid
+	regional.Serial, regional.SPKIHash = "2", "bb"
+	expired := valid
+	expired.NotAfter = time.Now().Add(-time.Minute)
+	intercepted := regional
+	intercepted.Issuer = "CN=Firewall CA"
+
+	analyze := func(country string, leaf utils.Certificate, verifyError, ocspStatus string) utils.Analyze {
+		return utils.Analyze{
+			CountryCode: country,
+			IpDest:      "192.0.2.1",
+			TLS: &utils.TLSInfo{
+				Chain:       []utils.Certificate{leaf},
+				VerifyError: verifyError,
+				OCSPStatus:  ocspStatus,
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		data     []utils.Analyze
+		expected []string
+	}{
+		{
+			name:     "No TLS",
+			data:     []utils.Analyze{{CountryCode: "FR"}},
+			expected: nil,
+		},
+		{
+			name: "Same certificate everywhere",
+			data: []utils.Analyze{
+				analyze("FR", valid, "", utils.OCSPGood),
+				analyze("DE", valid, "", utils.OCSPGood),
+			},
+			expected: nil,
+		},
+		{
+			name: "Regional certificate",
+			data: []utils.Analyze{
+				analyze("FR", valid, "", ""),
+				analyze("DE", valid, "", ""),
+				analyze("CN", regional, "", ""),
+			},
+			expected: []string{"CN (192.0.2.1) serves a regional certificate: serial 2 issued by CN=Public CA"},
+		},
+		{
+			name: "Expired edge node",
+			data: []utils.Analyze{
+				analyze("FR", valid, "", ""),
+				analyze("DE", expired, "", ""),
+			},
+			expected: []string{"DE (192.0.2.1) serves a certificate outside its validity (" +
+				expired.NotBefore.Format(time.DateOnly) + " to " + expired.NotAfter.Format(time.DateOnly) + ")"},
+		},
+		{
+			name: "Interception",
+			data: []utils.Analyze{
+				analyze("FR", valid, "", ""),
+				analyze("DE", valid, "", ""),
+				analyze("CN", intercepted, "x509: certificate signed by unknown authority", ""),
+			},
+			expected: []string{
+				"CN (192.0.2.1) serves a regional certificate: serial 2 issued by CN=Firewall CA",
+				"CN (192.0.2.1) certificate does not verify and has an unusual issuer CN=Firewall CA, interception suspected: x509: certificate signed by unknown authority",
+			},
+		},
+		{
+			name: "Revoked",
+			data: []utils.Analyze{
+				analyze("FR", valid, "", utils.OCSPRevoked),
+			},
+			expected: []string{"FR (192.0.2.1) staples an OCSP response saying the certificate is revoked"},
+		},
+		{
+			name: "Probe skipped",
+			data: func() []utils.Analyze {
+				probe := analyze("FR", regional, "x509: certificate is valid for other.example, not example.com", "")
+				probe.Probe = utils.ProbeHostMismatch
+				return []utils.Analyze{analyze("FR", valid, "", ""), probe}
+			}(),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompareCertificates(tt.data)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("CompareCertificates() got = %q, expected = %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package classifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	LabelContent   = "content"
+	LabelGeoBlock  = "geo-block"
+	LabelLegal     = "legal-block"
+	LabelChallenge = "challenge"
+	LabelCaptcha   = "captcha"
+	LabelError     = "error"
+)
+
+// Signature recognises a page, every condition given has to match. Headers and
+// the body and title patterns are regular expressions
+type Signature struct {
+	Name    string            `json:"name"`
+	Label   string            `json:"label"`
+	Status  []int             `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+	Title   string            `json:"title"`
+
+	headers map[string]*regexp.Regexp
+	body    *regexp.Regexp
+	title   *regexp.Regexp
+}
+
+// Response is what a signature is matched against
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Title      string
+}
+
+// Verdict is the label of a response and what gave it away
+type Verdict struct {
+	Label     string
+	Signature string
+	Detail    string
+}
+
+// matches the target of a RFC 7725 Link: <https://blocker.example>; rel="blocked-by"
+var blockedByPattern = regexp.MustCompile(`<([^>]*)>\s*;[^,]*rel="?blocked-by"?`)
+
+// Load reads a JSON list of signatures, a missing file leaves only the built-in RFC 7725 detection
+func Load(path string) ([]Signature, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		log.Printf("Signature file %s not found, only HTTP 451 is classified\n", path)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var signatures []Signature
+	if err := json.Unmarshal(content, &signatures); err != nil {
+		return nil, err
+	}
+
+	for i := range signatures {
+		if err := signatures[i].compile(); err != nil {
+			return nil, fmt.Errorf("signature %q: %w", signatures[i].Name, err)
+		}
+	}
+
+	return signatures, nil
+}
+
+func (s *Signature) compile() error {
+	var err error
+	s.headers = make(map[string]*regexp.Regexp, len(s.Headers))
+	for name, pattern := range s.Headers {
+		if s.headers[name], err = regexp.Compile(pattern); err != nil {
+			return err
+		}
+	}
+	if s.Body != "" {
+		if s.body, err = regexp.Compile(s.Body); err != nil {
+			return err
+		}
+	}
+	if s.Title != "" {
+		if s.title, err = regexp.Compile(s.Title); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Signature) match(resp Response) bool {
+	if len(s.Status) > 0 && !slices.Contains(s.Status, resp.StatusCode) {
+		return false
+	}
+	for name, re := range s.headers {
+		values := resp.Header.Values(name)
+		if len(values) == 0 || !re.MatchString(strings.Join(values, ", ")) {
+			return false
+		}
+	}
+	if s.body != nil && !s.body.Match(resp.Body) {
+		return false
+	}
+	if s.title != nil && !s.title.MatchString(resp.Title) {
+		return false
+	}
+	return true
+}
+
+// Classify labels a response with the first matching signature. HTTP 451 is
+// recognised without signature and its blocked-by Link is kept as detail
+func Classify(signatures []Signature, resp Response) Verdict {
+	if resp.StatusCode == http.StatusUnavailableForLegalReasons {
+		verdict := Verdict{Label: LabelLegal, Signature: "rfc7725"}
+		for _, link := range resp.Header.Values("Link") {
+			if m := blockedByPattern.FindStringSubmatch(link); m != nil {
+				verdict.Detail = "blocked by " + m[1]
+			}
+		}
+		return verdict
+	}
+
+	for i := range signatures {
+		if signatures[i].match(resp) {
+			return Verdict{Label: signatures[i].Label, Signature: signatures[i].Name}
+		}
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 400 {
+		return Verdict{Label: LabelContent}
+	}
+	return Verdict{Label: LabelError, Detail: fmt.Sprintf("no signature for status %d", resp.StatusCode)}
+}
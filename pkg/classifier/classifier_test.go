@@ -0,0 +1,67 @@
+package classifier
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	signatures := []Signature{
+		{Name: "cloudflare-challenge", Label: LabelChallenge, Status: []int{403, 503}, Headers: map[string]string{"cf-mitigated": "(?i)challenge"}},
+		{Name: "captcha-widget", Label: LabelCaptcha, Body: `class="g-recaptcha`},
+		{Name: "not-available-in-country", Label: LabelGeoBlock, Body: "(?i)not available in your country"},
+	}
+	for i := range signatures {
+		if err := signatures[i].compile(); err != nil {
+			t.Fatalf("compile() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		resp     Response
+		expected Verdict
+	}{
+		{
+			name: "RFC 7725 with blocked-by",
+			resp: Response{
+				StatusCode: 451,
+				Header:     http.Header{"Link": {`<https://authority.example/>; rel="blocked-by"`}},
+			},
+			expected: Verdict{Label: LabelLegal, Signature: "rfc7725", Detail: "blocked by https://authority.example/"},
+		},
+		{
+			name:     "Challenge header",
+			resp:     Response{StatusCode: 403, Header: http.Header{"Cf-Mitigated": {"challenge"}}},
+			expected: Verdict{Label: LabelChallenge, Signature: "cloudflare-challenge"},
+		},
+		{
+			name:     "Challenge header with another status",
+			resp:     Response{StatusCode: 200, Header: http.Header{"Cf-Mitigated": {"challenge"}}},
+			expected: Verdict{Label: LabelContent},
+		},
+		{
+			name:     "Geo block page served with 200",
+			resp:     Response{StatusCode: 200, Header: http.Header{}, Body: []byte("<p>This video is not available in your country.</p>")},
+			expected: Verdict{Label: LabelGeoBlock, Signature: "not-available-in-country"},
+		},
+		{
+			name:     "Captcha",
+			resp:     Response{StatusCode: 200, Header: http.Header{}, Body: []byte(`<div class="g-recaptcha" data-sitekey="x"></div>`)},
+			expected: Verdict{Label: LabelCaptcha, Signature: "captcha-widget"},
+		},
+		{
+			name:     "Unknown error",
+			resp:     Response{StatusCode: 502, Header: http.Header{}},
+			expected: Verdict{Label: LabelError, Detail: "no signature for status 502"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(signatures, tt.resp); got != tt.expected {
+				t.Errorf("Classify() got = %+v, expected = %+v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+// CompareCrawls compares the pages crawled from each country: pages only some countries reached
+// and pages whose hash differs between countries
+func CompareCrawls(data []utils.Analyze, budget uint) []string {
+	crawls := make(map[string]map[string]utils.Page)
+	full := make(map[string]bool)
+	for _, entry := range data {
+		if len(entry.Pages) == 0 || entry.IsProbe() {
+			continue
+		}
+		pages := make(map[string]utils.Page, len(entry.Pages))
+		for _, visited := range entry.Pages {
+			pages[visited.URL] = visited
+		}
+		crawls[entry.CountryCode] = pages
+		full[entry.CountryCode] = uint(len(entry.Pages)) >= budget
+	}
+	if len(crawls) < 2 {
+		return nil
+	}
+
+	countries := make([]string, 0, len(crawls))
+	urls := make(map[string]bool)
+	for code, pages := range crawls {
+		countries = append(countries, code)
+		for u := range pages {
+			urls[u] = true
+		}
+	}
+	sort.Strings(countries)
+	sorted := make([]string, 0, len(urls))
+	for u := range urls {
+		sorted = append(sorted, u)
+	}
+	sort.Strings(sorted)
+
+	var notes []string
+	for _, u := range sorted {
+		var found, missing []string
+		hashes := make(map[string][]string)
+		for _, code := range countries {
+			visited, ok := crawls[code][u]
+			if !ok {
+				// a country that ran out of budget may simply not have reached the page
+				if full[code] {
+					code += " (budget reached)"
+				}
+				missing = append(missing, code)
+				continue
+			}
+			found = append(found, code)
+			key := fmt.Sprintf("%d %x", visited.StatusCode, visited.Hash)
+			hashes[key] = append(hashes[key], code)
+		}
+
+		if len(missing) > 0 {
+			notes = append(notes, fmt.Sprintf("%s reached from %s only, not from %s",
+				u, strings.Join(found, ", "), strings.Join(missing, ", ")))
+		}
+		if len(hashes) > 1 {
+			notes = append(notes, fmt.Sprintf("%s differs: %s", u, describeVariants(hashes)))
+		}
+	}
+
+	return notes
+}
+
+func describeVariants(variants map[string][]string) string {
+	keys := make([]string, 0, len(variants))
+	for key := range variants {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s from %s", key, strings.Join(variants[key], ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func DisplayCrawl(notes []string) {
+	if len(notes) == 0 {
+		return
+	}
+
+	fmt.Println("Crawl:")
+	for _, note := range notes {
+		fmt.Printf("\t%s\n", note)
+	}
+	fmt.Println("")
+}
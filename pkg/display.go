@@ -1,9 +1,13 @@
 package pkg
 
 import (
+	"encoding/hex"
 	"fmt"
 	"sort"
+	"strings"
+	"time"
 
+	"github.com/OnsagerHe/geoip-detector/pkg/emulation"
 	"github.com/OnsagerHe/geoip-detector/pkg/utils"
 	pb "github.com/OnsagerHe/geoip-detector/proto/gen"
 	"github.com/fatih/color"
@@ -12,7 +16,7 @@ import (
 func countHashFrequencies(data []utils.Analyze) map[string]int {
 	frequencyMap := make(map[string]int)
 	for _, entry := range data {
-		hashStr := string(entry.Hash)
+		hashStr := string(entry.ComparisonHash())
 		frequencyMap[hashStr]++
 	}
 	return frequencyMap
@@ -20,8 +24,8 @@ func countHashFrequencies(data []utils.Analyze) map[string]int {
 
 func sortByHashFrequency(data []utils.Analyze, frequencyMap map[string]int) []utils.Analyze {
 	sort.Slice(data, func(i, j int) bool {
-		hashI := string(data[i].Hash)
-		hashJ := string(data[j].Hash)
+		hashI := string(data[i].ComparisonHash())
+		hashJ := string(data[j].ComparisonHash())
 		return frequencyMap[hashI] < frequencyMap[hashJ]
 	})
 	return data
@@ -32,35 +36,372 @@ func SortResult(data []utils.Analyze) {
 	_ = sortByHashFrequency(data, frequencyMap)
 }
 
-func DisplayInformation(data []utils.Analyze) *[]pb.PutEndpointResponse {
-	metadata := []pb.PutEndpointResponse{}
-	var status string
+func DisplayInformation(data []utils.Analyze) *pb.PutEndpointResponse {
+	response := &pb.PutEndpointResponse{}
 
 	for _, entry := range data {
 		var statusMsg string
-		if entry.Online {
-			status = "online"
-			statusMsg = color.GreenString("[+] Status: online")
-		} else {
-			status = "offline"
-			statusMsg = color.RedString("[-] Status: offline")
-		}
-		metadata = append(metadata, pb.PutEndpointResponse{
-			Ip:       entry.IpDest,
-			Status:   status,
-			Filname:  entry.Filename,
-			HashFile: string(entry.Hash),
+		switch entry.Status {
+		case utils.StatusOnline:
+			statusMsg = color.GreenString("[+] Status: %s (%d)", entry.Status, entry.StatusCode)
+		case utils.StatusRedirect, utils.StatusRateLimited, utils.StatusNotFound:
+			statusMsg = color.YellowString("[~] Status: %s (%d)", entry.Status, entry.StatusCode)
+		default:
+			statusMsg = color.RedString("[-] Status: %s (%d)", entry.Status, entry.StatusCode)
+		}
+
+		headers := make(map[string]string, len(entry.Headers))
+		for name, values := range entry.Headers {
+			headers[name] = strings.Join(values, ", ")
+		}
+		response.Metadata = append(response.Metadata, &pb.MetadataEndpoint{
+			Ip:                 entry.IpDest,
+			Status:             entry.Status,
+			Filename:           entry.Filename,
+			HashFile:           hex.EncodeToString(entry.Hash),
+			StatusCode:         int32(entry.StatusCode),
+			Headers:            headers,
+			ContentType:        entry.ContentType,
+			BodySize:           entry.BodySize,
+			CountryCode:        entry.CountryCode,
+			Profile:            entry.Profile.Name,
+			NormalizedHash:     hex.EncodeToString(entry.NormalizedHash),
+			NormalizeError:     entry.NormalizeError,
+			Diff:               entry.Diff,
+			DiffFilename:       entry.DiffFilename,
+			Timing:             timingMessage(entry.Timing),
+			Protocol:           entry.Protocol,
+			NegotiatedProtocol: entry.NegotiatedProtocol,
+			AltSvc:             entry.AltSvc,
+			Pages:              pagesMessage(entry.Pages),
+			Assets:             assetsMessage(entry.Assets),
+			Attempts:           int32(entry.Attempts),
+			Error:              entry.Error,
+			Truncated:          entry.Truncated,
+			Probe:              entry.Probe,
+			Steps:              stepsMessage(entry.Steps),
+			Visual:             visualMessage(entry.Visual),
+			Emulation:          emulationMessage(entry.Emulation, entry.CountryCode),
+			Tls:                tlsMessage(entry.TLS),
+			Verdict: &pb.Verdict{
+				Label:     entry.Verdict.Label,
+				Signature: entry.Verdict.Signature,
+				Detail:    entry.Verdict.Detail,
+			},
 		})
 
 		fmt.Printf("%s\n", statusMsg)
+		fmt.Printf("Profile: %s\n", entry.Profile.Name)
+		if entry.Probe != "" {
+			fmt.Printf("Probe: %s\n", entry.Probe)
+		}
+		if entry.Verdict.Label != "" {
+			fmt.Printf("Verdict: %s %s %s\n", entry.Verdict.Label, entry.Verdict.Signature, entry.Verdict.Detail)
+		}
 		fmt.Printf("IP Source: %v\n", entry.IpSource)
 		fmt.Printf("IP Dest: %s\n", entry.IpDest)
+		if entry.FromHint {
+			fmt.Printf("IP Dest from HTTPS record hint, ALPN: %v\n", entry.ALPN)
+		}
 		fmt.Printf("Hash: %x\n", entry.Hash)
+		if entry.NormalizedHash != nil {
+			fmt.Printf("Normalized hash: %x\n", entry.NormalizedHash)
+		}
+		if entry.NormalizeError != "" {
+			fmt.Printf("Normalization failed: %s\n", entry.NormalizeError)
+		}
+		fmt.Printf("Protocol: %s, negotiated %s\n", entry.Protocol, entry.NegotiatedProtocol)
+		if len(entry.AltSvc) > 0 {
+			fmt.Printf("Alt-Svc: %v (h3 %t)\n", entry.AltSvc, entry.HTTP3)
+		}
+		fmt.Printf("Body: %d bytes, %s\n", entry.BodySize, entry.ContentType)
+		if entry.Truncated {
+			fmt.Printf("Body truncated to %d bytes for comparison\n", *utils.MaxBody)
+		}
+		if entry.Error != "" {
+			fmt.Printf("Error after %d attempts: %s\n", entry.Attempts, entry.Error)
+		}
+		if entry.Timing != nil {
+			fmt.Printf("Timing: dns %s connect %s tls %s ttfb %s download %s total %s\n", entry.Timing.DNS, entry.Timing.Connect,
+				entry.Timing.TLS, entry.Timing.TTFB, entry.Timing.Download, entry.Timing.Total)
+		}
+		if entry.TLS != nil {
+			fmt.Printf("TLS: %s %s alpn=%q ocsp=%s\n", entry.TLS.Version, entry.TLS.CipherSuite, entry.TLS.ALPN, entry.TLS.OCSPStatus)
+			if len(entry.TLS.Chain) > 0 {
+				leaf := entry.TLS.Chain[0]
+				fmt.Printf("Certificate: %s issued by %s, serial %s, spki %s\n", leaf.Subject, leaf.Issuer, leaf.Serial, leaf.SPKIHash)
+			}
+			if entry.TLS.VerifyError != "" {
+				fmt.Printf("Certificate verification: %s\n", entry.TLS.VerifyError)
+			}
+		}
+		if len(entry.Redirects) > 1 {
+			fmt.Printf("Redirects (%s):\n", entry.RedirectClass)
+			for _, hop := range entry.Redirects {
+				fmt.Printf("\t%d %s -> %s (%s, %d cookies)\n", hop.StatusCode, hop.URL, hop.Location, hop.Duration, len(hop.SetCookie))
+			}
+		}
 		// fmt.Printf("Hour UTC: %s\n", "N/A") // TODO: Maybe add timestamp
 		fmt.Printf("Country Code IP Source: %s\n", entry.CountryCode)
+		fmt.Printf("Provider: %s %v\n", entry.Provider.Name, entry.Provider.Evidence)
 		fmt.Printf("Filename screenshot: %s\n", entry.Filename)
-		fmt.Printf("Nameserver requested: %s\n\n", entry.Nameserver.IPs)
+		if entry.Visual != nil && entry.Visual.Baseline != "" {
+			fmt.Printf("Visual diff against %s: %.2f%% pixels, phash distance %d %s\n", entry.Visual.Baseline,
+				entry.Visual.DiffPercent, entry.Visual.Distance, entry.Visual.DiffFilename)
+		}
+		if entry.Emulation != nil {
+			fmt.Printf("Emulation: timezone %q locale %q geolocation %.4f,%.4f", entry.Emulation.Timezone, entry.Emulation.Locale,
+				entry.Emulation.Latitude, entry.Emulation.Longitude)
+			if mismatch := entry.Emulation.Mismatch(entry.CountryCode); len(mismatch) > 0 {
+				fmt.Printf(" (mismatch: %v)", mismatch)
+			}
+			fmt.Println("")
+		}
+		for _, step := range entry.Steps {
+			if step.Error != "" {
+				fmt.Printf("Scenario step %d %s failed: %s\n", step.Index, step.Action, step.Error)
+				continue
+			}
+			fmt.Printf("Scenario step %d %s: %s %s\n", step.Index, step.Action, step.Screenshot, step.DOM)
+		}
+		if len(entry.Assets) > 0 {
+			fmt.Printf("Subresources: %d\n", len(entry.Assets))
+		}
+		if len(entry.Pages) > 0 {
+			fmt.Printf("Crawled pages: %d\n", len(entry.Pages))
+		}
+		if entry.Diff != "" {
+			fmt.Printf("Diff against baseline: %s\n%s", entry.DiffFilename, entry.Diff)
+		}
+		fmt.Printf("Nameserver requested: %s\n", entry.Nameserver.IPs)
+		fmt.Printf("DNS integrity: %s %v\n\n", entry.DNSIntegrity.Verdict, entry.DNSIntegrity.Flags)
 	}
 
-	return &metadata
+	return response
+}
+
+func DisplaySteering(report utils.SteeringReport) {
+	fmt.Printf("DNS steering: %s\n", report.Behaviour)
+
+	countries := make([]string, 0, len(report.Countries))
+	for code := range report.Countries {
+		countries = append(countries, code)
+	}
+	sort.Strings(countries)
+
+	for _, code := range countries {
+		c := report.Countries[code]
+		fmt.Printf("\t%s: %s (confidence %.2f, %d answer sets over %d samples)\n",
+			code, c.Behaviour, c.Confidence, c.AnswerSets, c.Samples)
+	}
+	fmt.Println("")
+}
+
+// DisplayProviders lists the providers serving each country, so we know whom to contact for a region
+func DisplayProviders(data []utils.Analyze) {
+	providers := make(map[string]map[string]bool)
+	for _, entry := range data {
+		if providers[entry.CountryCode] == nil {
+			providers[entry.CountryCode] = make(map[string]bool)
+		}
+		name := entry.Provider.Name
+		if name == "" {
+			name = "unknown"
+		}
+		providers[entry.CountryCode][name] = true
+	}
+
+	countries := make([]string, 0, len(providers))
+	for code := range providers {
+		countries = append(countries, code)
+	}
+	sort.Strings(countries)
+
+	fmt.Println("Providers per region:")
+	for _, code := range countries {
+		names := make([]string, 0, len(providers[code]))
+		for name := range providers[code] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Printf("\t%s: %s\n", code, strings.Join(names, ", "))
+	}
+	fmt.Println("")
+}
+
+func DisplayZones(zones []utils.ZoneReport) {
+	for _, zone := range zones {
+		if len(zone.Unsynchronised) == 0 && len(zone.Diverging) == 0 {
+			fmt.Printf("Zone %s from %s: %d nameserver IPs in sync\n", zone.Zone, zone.CountryCode, len(zone.Servers))
+			continue
+		}
+
+		fmt.Printf("Zone %s from %s: %d unsynchronised, %d diverging\n",
+			zone.Zone, zone.CountryCode, len(zone.Unsynchronised), len(zone.Diverging))
+		for _, note := range zone.Notes {
+			fmt.Printf("\t%s\n", note)
+		}
+	}
+	fmt.Println("")
+}
+
+// DisplayHTTPSRecords prints the HTTPS records once per country and nameserver IP
+func DisplayHTTPSRecords(observations []utils.DNSObservation) {
+	for _, obs := range observations {
+		for _, record := range obs.HTTPS {
+			fmt.Printf("HTTPS record from %s via %s (%s): priority %d target %s alpn %v ipv4hint %v ipv6hint %v ech %t\n",
+				obs.CountryCode, obs.Nameserver, obs.Server, record.Priority, record.Target,
+				record.ALPN, record.IPv4Hint, record.IPv6Hint, len(record.ECH) > 0)
+		}
+	}
+}
+
+func timingMessage(timing *utils.Timing) *pb.Timing {
+	if timing == nil {
+		return nil
+	}
+	return &pb.Timing{
+		DnsMs:      timing.DNS.Milliseconds(),
+		ConnectMs:  timing.Connect.Milliseconds(),
+		TlsMs:      timing.TLS.Milliseconds(),
+		TtfbMs:     timing.TTFB.Milliseconds(),
+		DownloadMs: timing.Download.Milliseconds(),
+		TotalMs:    timing.Total.Milliseconds(),
+	}
+}
+
+func pagesMessage(pages []utils.Page) []*pb.CrawledPage {
+	var messages []*pb.CrawledPage
+	for _, visited := range pages {
+		messages = append(messages, &pb.CrawledPage{
+			Url:        visited.URL,
+			Depth:      uint32(visited.Depth),
+			StatusCode: int32(visited.StatusCode),
+			Hash:       hex.EncodeToString(visited.Hash),
+		})
+	}
+	return messages
+}
+
+func assetsMessage(assets []utils.Asset) []*pb.Asset {
+	var messages []*pb.Asset
+	for _, asset := range assets {
+		messages = append(messages, &pb.Asset{
+			Url:         asset.URL,
+			Kind:        asset.Kind,
+			StatusCode:  int32(asset.StatusCode),
+			ContentType: asset.ContentType,
+			Size:        asset.Size,
+			Hash:        hex.EncodeToString(asset.Hash),
+			Error:       asset.Error,
+		})
+	}
+	return messages
+}
+
+// DisplayVerdicts counts the verdicts of each country, a geo-block and a bot challenge are told apart
+func DisplayVerdicts(data []utils.Analyze) {
+	verdicts := make(map[string]map[string]int)
+	for _, entry := range data {
+		if entry.Verdict.Label == "" || entry.IsProbe() {
+			continue
+		}
+		if verdicts[entry.CountryCode] == nil {
+			verdicts[entry.CountryCode] = make(map[string]int)
+		}
+		verdicts[entry.CountryCode][entry.Verdict.Label]++
+	}
+	if len(verdicts) == 0 {
+		return
+	}
+
+	countries := make([]string, 0, len(verdicts))
+	for code := range verdicts {
+		countries = append(countries, code)
+	}
+	sort.Strings(countries)
+
+	fmt.Println("Verdicts per region:")
+	for _, code := range countries {
+		labels := make([]string, 0, len(verdicts[code]))
+		for label, n := range verdicts[code] {
+			labels = append(labels, fmt.Sprintf("%s x%d", label, n))
+		}
+		sort.Strings(labels)
+		fmt.Printf("\t%s: %s\n", code, strings.Join(labels, ", "))
+	}
+	fmt.Println("")
+}
+
+func stepsMessage(steps []utils.StepResult) []*pb.ScenarioStep {
+	var messages []*pb.ScenarioStep
+	for _, step := range steps {
+		messages = append(messages, &pb.ScenarioStep{
+			Index:      int32(step.Index),
+			Action:     step.Action,
+			Name:       step.Name,
+			Screenshot: step.Screenshot,
+			Dom:        step.DOM,
+			DomHash:    hex.EncodeToString(step.DOMHash),
+			Error:      step.Error,
+		})
+	}
+	return messages
+}
+
+func visualMessage(visual *utils.VisualDiff) *pb.Visual {
+	if visual == nil {
+		return nil
+	}
+	return &pb.Visual{
+		Baseline:       visual.Baseline,
+		DiffPercent:    visual.DiffPercent,
+		PerceptualHash: fmt.Sprintf("%016x", visual.PerceptualHash),
+		Distance:       int32(visual.Distance),
+		DiffFilename:   visual.DiffFilename,
+		Flagged:        visual.Flagged,
+	}
+}
+
+func emulationMessage(settings *emulation.Settings, countryCode string) *pb.Emulation {
+	if settings == nil {
+		return nil
+	}
+	return &pb.Emulation{
+		Timezone:           settings.Timezone,
+		TimezoneCountry:    settings.TimezoneCountry,
+		Locale:             settings.Locale,
+		AcceptLanguage:     settings.AcceptLanguage,
+		LocaleCountry:      settings.LocaleCountry,
+		Latitude:           settings.Latitude,
+		Longitude:          settings.Longitude,
+		GeolocationCountry: settings.GeolocationCountry,
+		Mismatch:           settings.Mismatch(countryCode),
+	}
+}
+
+func tlsMessage(info *utils.TLSInfo) *pb.TLSInfo {
+	if info == nil {
+		return nil
+	}
+	message := &pb.TLSInfo{
+		Version:     info.Version,
+		CipherSuite: info.CipherSuite,
+		Alpn:        info.ALPN,
+		ServerName:  info.ServerName,
+		OcspStatus:  info.OCSPStatus,
+		VerifyError: info.VerifyError,
+	}
+	for _, cert := range info.Chain {
+		message.Chain = append(message.Chain, &pb.Certificate{
+			Subject:   cert.Subject,
+			Sans:      cert.SANs,
+			Issuer:    cert.Issuer,
+			Serial:    cert.Serial,
+			NotBefore: cert.NotBefore.Format(time.RFC3339),
+			NotAfter:  cert.NotAfter.Format(time.RFC3339),
+			SpkiHash:  cert.SPKIHash,
+		})
+	}
+	return message
 }
@@ -5,11 +5,19 @@ import (
 	"log"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+	"golang.org/x/net/dns/dnsmessage"
 )
 
 func InitNameserversInformation(resource *utils.EndpointMetadata) error {
+	// an IP literal has no CNAME nor nameserver
+	if resource.IsIPLiteral() {
+		resource.CnameHost = resource.Host
+		return nil
+	}
+
 	err := checkCNAME(resource)
 	if err != nil {
 		log.Printf("Error: %v\n", err)
@@ -35,6 +43,7 @@ func getNs(resource *utils.EndpointMetadata) error {
 		subDomain = strings.Join(parts[i:], ".")
 		nsRecords, _ = net.LookupNS(subDomain)
 		if len(nsRecords) > 0 {
+			resource.Zone = subDomain
 			break
 		}
 	}
@@ -89,24 +98,85 @@ func filterIPv6Str(addresses *[]string) {
 }
 
 func ProcessDNSRecords(res *utils.GeoIP, countryCode string, ips []string, ns utils.Nameserver, ip net.IP) []string {
-	var analyze utils.Analyze
+	start := time.Now()
 	host, err := net.LookupHost(res.Resource.CnameHost)
 	if err != nil {
 		log.Println("Error looking up host:", err)
 		return nil
 	}
+	resolution := time.Since(start)
 	filterIPv6Str(&host)
 
+	// HTTPS records may advertise other addresses and protocols, they are tested as well
+	hints, alpn := HTTPSHints(res, countryCode, ip)
+	known := make(map[string]bool)
 	for _, h := range host {
-		analyze.IpDest = h
-		analyze.CountryCode = countryCode
-		analyze.IpSource = ips
-		analyze.Nameserver = utils.Nameserver{Host: ns.Host, IPs: []net.IP{ip}}
-		res.Analyzes = append(res.Analyzes, analyze)
+		known[h] = true
+	}
+
+	for _, hint := range hints {
+		if !known[hint] {
+			host = append(host, hint)
+		}
 	}
+
+	appendAnalyzes(res, countryCode, ips, utils.Nameserver{Host: ns.Host, IPs: []net.IP{ip}}, host, known, alpn, ECHConfig(res, countryCode, ip), resolution)
+	return host
+}
+
+// ProcessLiteral creates the analyzes of an endpoint given as an IP literal, there is no DNS stage to go through
+func ProcessLiteral(res *utils.GeoIP, countryCode string, ips []string) []string {
+	host := []string{res.Resource.Host}
+	appendAnalyzes(res, countryCode, ips, utils.Nameserver{}, host, map[string]bool{res.Resource.Host: true}, nil, nil, 0)
 	return host
 }
 
+// appendAnalyzes creates one analyze per destination, request profile and forced protocol, then the probes
+func appendAnalyzes(res *utils.GeoIP, countryCode string, ips []string, ns utils.Nameserver, host []string, known map[string]bool, alpn []string, ech []byte, resolution time.Duration) {
+	var analyze utils.Analyze
+	for _, h := range host {
+		for _, profile := range res.Profiles {
+			for _, protocol := range res.Protocols {
+				analyze.IpDest = h
+				analyze.CountryCode = countryCode
+				analyze.IpSource = ips
+				analyze.Nameserver = ns
+				analyze.ALPN = alpn
+				analyze.FromHint = !known[h]
+				analyze.Profile = profile
+				analyze.Protocol = protocol
+				analyze.Resolution = resolution
+				res.Analyzes = append(res.Analyzes, analyze)
+			}
+		}
+	}
+
+	// probes only make sense over TLS, they use the first profile and protocol
+	if res.Resource.Scheme != "https" || len(res.Profiles) == 0 || len(res.Protocols) == 0 {
+		return
+	}
+	for _, h := range host {
+		for _, probe := range res.Probes {
+			if probe == utils.ProbeECH && ech == nil {
+				continue
+			}
+			res.Analyzes = append(res.Analyzes, utils.Analyze{
+				IpDest:      h,
+				CountryCode: countryCode,
+				IpSource:    ips,
+				Nameserver:  ns,
+				ALPN:        alpn,
+				FromHint:    !known[h],
+				Profile:     res.Profiles[0],
+				Protocol:    res.Protocols[0],
+				Probe:       probe,
+				ECHConfig:   ech,
+				Resolution:  resolution,
+			})
+		}
+	}
+}
+
 func checkCNAME(resource *utils.EndpointMetadata) error {
 	var err error
 
@@ -116,5 +186,46 @@ func checkCNAME(resource *utils.EndpointMetadata) error {
 		return err
 	}
 
+	resource.CnameChain = cnameChain(resource.Host)
+	resource.Cname = len(resource.CnameChain) > 0
+
 	return nil
 }
+
+// cnameChain follows the CNAME records of host one hop at a time, LookupCNAME only gives the last one
+func cnameChain(host string) []string {
+	const maxHops = 10
+	var chain []string
+
+	resolver, err := VantageResolver()
+	if err != nil {
+		log.Printf("Error: %v\n", err)
+		return nil
+	}
+
+	name := dnsFQDN(host)
+	for i := 0; i < maxHops; i++ {
+		msg, err := exchange(resolver, name, dnsmessage.TypeCNAME)
+		if err != nil {
+			log.Printf("Error looking up CNAME chain: %v\n", err)
+			break
+		}
+
+		next := ""
+		for _, rr := range msg.Answers {
+			body, ok := rr.Body.(*dnsmessage.CNAMEResource)
+			if ok && strings.EqualFold(rr.Header.Name.String(), name) {
+				next = body.CNAME.String()
+				break
+			}
+		}
+		if next == "" {
+			break
+		}
+
+		chain = append(chain, next)
+		name = next
+	}
+
+	return chain
+}
@@ -0,0 +1,291 @@
+package dns
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/netip"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	IntegrityClean          = "clean"
+	IntegrityUnknown        = "unknown"
+	IntegrityMismatch       = "mismatch"
+	IntegrityNXDomain       = "nxdomain"
+	IntegrityNXDomainHijack = "nxdomain-hijack"
+	IntegrityBogon          = "bogon"
+	IntegritySinkhole       = "sinkhole"
+	IntegrityInjected       = "injected"
+)
+
+// how long we keep listening for racing answers after the first one
+const injectionWait = 2 * time.Second
+
+// flags ordered from the most to the least severe, the first one found becomes the verdict
+var integritySeverity = []string{
+	IntegrityInjected,
+	IntegritySinkhole,
+	IntegrityBogon,
+	IntegrityNXDomainHijack,
+	IntegrityNXDomain,
+	IntegrityMismatch,
+}
+
+var bogonPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("169.254.0.0/16"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("224.0.0.0/4"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("::/128"),
+	netip.MustParsePrefix("::1/128"),
+	netip.MustParsePrefix("fc00::/7"),
+	netip.MustParsePrefix("fe80::/10"),
+	netip.MustParsePrefix("2001:db8::/32"),
+	netip.MustParsePrefix("ff00::/8"),
+}
+
+// LoadSinkholes reads one IP address or CIDR prefix per line, lines starting with # are ignored
+func LoadSinkholes(path string) ([]netip.Prefix, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var prefixes []netip.Prefix
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.Contains(line, "/") {
+			addr, err := netip.ParseAddr(line)
+			if err != nil {
+				return nil, fmt.Errorf("invalid sinkhole %q: %w", line, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sinkhole %q: %w", line, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, scanner.Err()
+}
+
+// VantageResolver returns the resolver set with -resolver, or the first nameserver of /etc/resolv.conf
+func VantageResolver() (net.IP, error) {
+	if utils.Resolver != nil && *utils.Resolver != "" {
+		ip := net.ParseIP(*utils.Resolver)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid resolver %q", *utils.Resolver)
+		}
+		return ip, nil
+	}
+
+	file, err := os.Open("/etc/resolv.conf")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			if ip := net.ParseIP(fields[1]); ip != nil {
+				return ip, nil
+			}
+		}
+	}
+
+	return nil, errors.New("no nameserver in /etc/resolv.conf")
+}
+
+// CheckIntegrity asks the vantage resolver for the endpoint host and compares the answers
+// with the ones the authoritative nameservers gave from the same country
+func CheckIntegrity(res *utils.GeoIP, countryCode string, resolver net.IP, sinkholes []netip.Prefix) utils.DNSIntegrity {
+	authoritative, authNX := authoritativeAnswers(res.Observations, countryCode)
+
+	responses, err := exchangeAll(resolver, res.Resource.Host, dnsmessage.TypeA, injectionWait)
+	if err != nil {
+		log.Printf("Error querying resolver %s: %v\n", resolver, err)
+		return utils.DNSIntegrity{
+			Verdict:       IntegrityUnknown,
+			Resolver:      resolver.String(),
+			Authoritative: authoritative,
+		}
+	}
+
+	var answers [][]string
+	var nxdomain bool
+	for _, msg := range responses {
+		answers = append(answers, answerIPs(msg))
+		if msg.RCode == dnsmessage.RCodeNameError {
+			nxdomain = true
+		}
+	}
+
+	integrity := assessIntegrity(answers, nxdomain, authoritative, authNX, sinkholes)
+	integrity.Resolver = resolver.String()
+	return integrity
+}
+
+// authoritativeAnswers merges every IP the nameservers returned for countryCode,
+// authNX is true when all of them answered NXDOMAIN
+func authoritativeAnswers(observations []utils.DNSObservation, countryCode string) ([]string, bool) {
+	var ips []string
+	seen := make(map[string]bool)
+	var samples, nxdomain uint
+
+	for _, obs := range observations {
+		if obs.CountryCode != countryCode {
+			continue
+		}
+		samples += obs.Samples
+		nxdomain += obs.NXDomain
+		for set := range obs.AnswerSets {
+			for _, ip := range splitAnswerSet(set) {
+				if !seen[ip] {
+					seen[ip] = true
+					ips = append(ips, ip)
+				}
+			}
+		}
+	}
+
+	return ips, samples > 0 && nxdomain == samples
+}
+
+func assessIntegrity(answers [][]string, nxdomain bool, authoritative []string, authNX bool, sinkholes []netip.Prefix) utils.DNSIntegrity {
+	integrity := utils.DNSIntegrity{
+		Authoritative: authoritative,
+		Responses:     len(answers),
+	}
+	flags := make(map[string]bool)
+
+	seen := make(map[string]bool)
+	for _, set := range answers {
+		for _, ip := range set {
+			if !seen[ip] {
+				seen[ip] = true
+				integrity.Answers = append(integrity.Answers, ip)
+			}
+		}
+	}
+
+	// a resolver answers once, two different responses to the same query means one was injected
+	for k := 1; k < len(answers); k++ {
+		if !sameIPs(answers[k], answers[0]) {
+			flags[IntegrityInjected] = true
+		}
+	}
+
+	fromAuthoritative := make(map[string]bool, len(authoritative))
+	for _, ip := range authoritative {
+		fromAuthoritative[ip] = true
+	}
+	for _, ip := range integrity.Answers {
+		addr, err := netip.ParseAddr(ip)
+		if err != nil {
+			continue
+		}
+		addr = addr.Unmap()
+		if containsAddr(sinkholes, addr) {
+			flags[IntegritySinkhole] = true
+		}
+		// a private address published by the zone itself is not tampering
+		if containsAddr(bogonPrefixes, addr) && !fromAuthoritative[ip] {
+			flags[IntegrityBogon] = true
+		}
+	}
+
+	switch {
+	case authNX && len(integrity.Answers) > 0:
+		flags[IntegrityNXDomainHijack] = true
+	case nxdomain && len(authoritative) > 0:
+		flags[IntegrityNXDomain] = true
+	case len(authoritative) > 0 && len(integrity.Answers) > 0 && !overlaps(integrity.Answers, authoritative):
+		flags[IntegrityMismatch] = true
+	}
+
+	integrity.Verdict = IntegrityClean
+	if len(authoritative) == 0 && !authNX {
+		integrity.Verdict = IntegrityUnknown
+	}
+	for _, flag := range integritySeverity {
+		if !flags[flag] {
+			continue
+		}
+		if len(integrity.Flags) == 0 {
+			integrity.Verdict = flag
+		}
+		integrity.Flags = append(integrity.Flags, flag)
+	}
+
+	return integrity
+}
+
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameIPs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool)
+	for _, ip := range a {
+		set[ip] = true
+	}
+	for _, ip := range b {
+		if !set[ip] {
+			return false
+		}
+	}
+	return true
+}
+
+func overlaps(a, b []string) bool {
+	set := make(map[string]bool)
+	for _, ip := range a {
+		set[ip] = true
+	}
+	for _, ip := range b {
+		if set[ip] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package dns
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAssessIntegrity(t *testing.T) {
+	sinkholes := []netip.Prefix{netip.MustParsePrefix("146.112.61.0/24")}
+
+	tests := []struct {
+		name          string
+		answers       [][]string
+		nxdomain      bool
+		authoritative []string
+		authNX        bool
+		verdict       string
+		flags         []string
+	}{
+		{
+			name:          "Clean",
+			answers:       [][]string{{"93.184.216.34"}},
+			authoritative: []string{"93.184.216.34"},
+			verdict:       IntegrityClean,
+		},
+		{
+			name:    "No authoritative answer",
+			answers: [][]string{{"93.184.216.34"}},
+			verdict: IntegrityUnknown,
+		},
+		{
+			name:          "Injection race",
+			answers:       [][]string{{"93.184.216.34"}, {"8.7.198.45"}},
+			authoritative: []string{"93.184.216.34"},
+			verdict:       IntegrityInjected,
+			flags:         []string{IntegrityInjected},
+		},
+		{
+			name:          "Sinkhole",
+			answers:       [][]string{{"146.112.61.104"}},
+			authoritative: []string{"93.184.216.34"},
+			verdict:       IntegritySinkhole,
+			flags:         []string{IntegritySinkhole, IntegrityMismatch},
+		},
+		{
+			name:          "Bogon",
+			answers:       [][]string{{"10.10.34.34"}},
+			authoritative: []string{"93.184.216.34"},
+			verdict:       IntegrityBogon,
+			flags:         []string{IntegrityBogon, IntegrityMismatch},
+		},
+		{
+			name:          "Private address published by the zone",
+			answers:       [][]string{{"10.0.0.5"}},
+			authoritative: []string{"10.0.0.5"},
+			verdict:       IntegrityClean,
+		},
+		{
+			name:    "NXDOMAIN rewritten to an address",
+			answers: [][]string{{"198.105.244.11"}},
+			authNX:  true,
+			verdict: IntegrityNXDomainHijack,
+			flags:   []string{IntegrityNXDomainHijack},
+		},
+		{
+			name:          "NXDOMAIN for an existing name",
+			answers:       [][]string{nil},
+			nxdomain:      true,
+			authoritative: []string{"93.184.216.34"},
+			verdict:       IntegrityNXDomain,
+			flags:         []string{IntegrityNXDomain},
+		},
+		{
+			name:          "Mismatch",
+			answers:       [][]string{{"203.119.1.1"}},
+			authoritative: []string{"93.184.216.34"},
+			verdict:       IntegrityMismatch,
+			flags:         []string{IntegrityMismatch},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := assessIntegrity(tt.answers, tt.nxdomain, tt.authoritative, tt.authNX, sinkholes)
+			if got.Verdict != tt.verdict {
+				t.Errorf("assessIntegrity() verdict = %s, expected %s", got.Verdict, tt.verdict)
+			}
+			if !reflect.DeepEqual(got.Flags, tt.flags) {
+				t.Errorf("assessIntegrity() flags = %v, expected %v", got.Flags, tt.flags)
+			}
+		})
+	}
+}
+
+func TestLoadSinkholes(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []netip.Prefix
+		hasError bool
+	}{
+		{
+			name:    "Addresses and prefixes",
+			content: "# OpenDNS\n146.112.61.104\n\n10.1.2.3/8\n::1\n",
+			expected: []netip.Prefix{
+				netip.MustParsePrefix("146.112.61.104/32"),
+				netip.MustParsePrefix("10.0.0.0/8"),
+				netip.MustParsePrefix("::1/128"),
+			},
+		},
+		{
+			name:     "Invalid line",
+			content:  "not-an-ip\n",
+			hasError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "sinkholes")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadSinkholes(path)
+			if (err != nil) != tt.hasError {
+				t.Fatalf("LoadSinkholes() error = %v, hasError %v", err, tt.hasError)
+			}
+			if !tt.hasError && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("LoadSinkholes() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+
+	if got, err := LoadSinkholes(""); got != nil || err != nil {
+		t.Errorf("LoadSinkholes(\"\") = %v, %v, expected nothing", got, err)
+	}
+}
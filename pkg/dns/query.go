@@ -0,0 +1,127 @@
+package dns
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const queryTimeout = 3 * time.Second
+
+// buildQuery packs a single question for name with the given type
+func buildQuery(id uint16, name string, qtype dnsmessage.Type) ([]byte, error) {
+	qname, err := dnsmessage.NewName(dnsFQDN(name))
+	if err != nil {
+		return nil, err
+	}
+
+	msg := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{
+			Name:  qname,
+			Type:  qtype,
+			Class: dnsmessage.ClassINET,
+		}},
+	}
+	return msg.Pack()
+}
+
+// exchange sends a question to server over UDP and returns the first matching response
+func exchange(server net.IP, name string, qtype dnsmessage.Type) (*dnsmessage.Message, error) {
+	id := uint16(time.Now().UnixNano())
+	query, err := buildQuery(id, name, qtype)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout("udp", net.JoinHostPort(server.String(), "53"), queryTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(queryTimeout)); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, fmt.Errorf("no answer from %s: %w", server, err)
+		}
+		var msg dnsmessage.Message
+		if err := msg.Unpack(buf[:n]); err != nil || msg.ID != id {
+			continue
+		}
+		return &msg, nil
+	}
+}
+
+// exchangeAll sends a question to server over UDP and keeps listening until wait
+// expires, so that racing answers injected on path are collected as well
+func exchangeAll(server net.IP, name string, qtype dnsmessage.Type, wait time.Duration) ([]*dnsmessage.Message, error) {
+	id := uint16(time.Now().UnixNano())
+	query, err := buildQuery(id, name, qtype)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout("udp", net.JoinHostPort(server.String(), "53"), queryTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(wait)); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+
+	var responses []*dnsmessage.Message
+	buf := make([]byte, 4096)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			break
+		}
+		var msg dnsmessage.Message
+		if err := msg.Unpack(buf[:n]); err != nil || msg.ID != id {
+			continue
+		}
+		responses = append(responses, &msg)
+	}
+
+	if len(responses) == 0 {
+		return nil, fmt.Errorf("no answer from %s", server)
+	}
+	return responses, nil
+}
+
+// answerIPs returns the A and AAAA records contained in the answer section
+func answerIPs(msg *dnsmessage.Message) []string {
+	var ips []string
+	for _, rr := range msg.Answers {
+		switch body := rr.Body.(type) {
+		case *dnsmessage.AResource:
+			ips = append(ips, net.IP(body.A[:]).String())
+		case *dnsmessage.AAAAResource:
+			ips = append(ips, net.IP(body.AAAA[:]).String())
+		}
+	}
+	return ips
+}
+
+func dnsFQDN(name string) string {
+	if len(name) > 0 && name[len(name)-1] == '.' {
+		return name
+	}
+	return name + "."
+}
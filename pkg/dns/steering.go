@@ -0,0 +1,192 @@
+package dns
+
+import (
+	"log"
+	"net"
+	"strings"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	BehaviourUnknown    = "unknown"
+	BehaviourStatic     = "static"
+	BehaviourRoundRobin = "round-robin"
+	BehaviourWeighted   = "weighted"
+	BehaviourGeo        = "geo"
+)
+
+// minimum share of a country's samples carrying an IP never seen elsewhere to call it geo-steered
+const geoExclusiveShare = 0.5
+
+// imbalance (dominant share times number of answer sets) above which a rotation is weighted
+const weightedImbalance = 1.5
+
+// SampleNameserver queries the nameserver ip several times for the CNAME host
+// and records the distribution of answer sets seen from countryCode
+func SampleNameserver(res *utils.GeoIP, countryCode string, ns utils.Nameserver, ip net.IP, samples uint) []string {
+	observation := utils.DNSObservation{
+		CountryCode: countryCode,
+		Nameserver:  ns.Host.Host,
+		Server:      ip,
+		AnswerSets:  make(map[string]uint),
+	}
+	seen := make(map[string]bool)
+	var union []string
+
+	for i := uint(0); i < samples; i++ {
+		msg, err := exchange(ip, res.Resource.CnameHost, dnsmessage.TypeA)
+		if err != nil {
+			log.Printf("Error sampling nameserver %s: %v\n", ip, err)
+			continue
+		}
+
+		if msg.RCode == dnsmessage.RCodeNameError {
+			observation.NXDomain++
+		}
+
+		// order is kept on purpose: plain round-robin only rotates the records
+		ips := answerIPs(msg)
+		observation.AnswerSets[strings.Join(ips, ",")]++
+		observation.Samples++
+
+		for _, addr := range ips {
+			if !seen[addr] {
+				seen[addr] = true
+				union = append(union, addr)
+			}
+		}
+	}
+
+	observation.HTTPS = queryHTTPSRecords(ip, res.Resource.CnameHost)
+
+	res.Observations = append(res.Observations, observation)
+	return union
+}
+
+type countrySamples struct {
+	samples    uint
+	answerSets map[string]uint
+	rotating   bool
+}
+
+// ClassifySteering tells geo-steering apart from round-robin and weighted
+// rotation from the answer set distributions of every country
+func ClassifySteering(observations []utils.DNSObservation) utils.SteeringReport {
+	report := utils.SteeringReport{
+		Behaviour: BehaviourUnknown,
+		Countries: make(map[string]utils.CountrySteering),
+	}
+
+	countries := make(map[string]*countrySamples)
+	ipCountries := make(map[string]map[string]bool)
+	for _, obs := range observations {
+		if obs.Samples == 0 {
+			continue
+		}
+		c, ok := countries[obs.CountryCode]
+		if !ok {
+			c = &countrySamples{answerSets: make(map[string]uint)}
+			countries[obs.CountryCode] = c
+		}
+		c.samples += obs.Samples
+		// rotation only counts when the same nameserver IP changes its answer
+		if len(obs.AnswerSets) > 1 {
+			c.rotating = true
+		}
+		for set, count := range obs.AnswerSets {
+			c.answerSets[set] += count
+			for _, ip := range splitAnswerSet(set) {
+				if ipCountries[ip] == nil {
+					ipCountries[ip] = make(map[string]bool)
+				}
+				ipCountries[ip][obs.CountryCode] = true
+			}
+		}
+	}
+
+	if len(countries) == 0 {
+		return report
+	}
+
+	geo, rotation := false, ""
+	for code, c := range countries {
+		steering := utils.CountrySteering{
+			Samples:    c.samples,
+			AnswerSets: len(c.answerSets),
+		}
+		sufficiency := float64(c.samples) / float64(c.samples+1)
+
+		if c.rotating && rotation != BehaviourWeighted {
+			rotation = rotationBehaviour(c)
+		}
+
+		exclusive := exclusiveShare(code, c, ipCountries)
+		switch {
+		case len(countries) > 1 && exclusive >= geoExclusiveShare:
+			steering.Behaviour = BehaviourGeo
+			steering.Confidence = exclusive * sufficiency
+			geo = true
+		case c.rotating:
+			steering.Behaviour = rotationBehaviour(c)
+			steering.Confidence = sufficiency
+		default:
+			steering.Behaviour = BehaviourStatic
+			steering.Confidence = dominantShare(c) * sufficiency
+		}
+		report.Countries[code] = steering
+	}
+
+	switch {
+	case geo && rotation != "":
+		report.Behaviour = BehaviourGeo + "+" + rotation
+	case geo:
+		report.Behaviour = BehaviourGeo
+	case rotation != "":
+		report.Behaviour = rotation
+	default:
+		report.Behaviour = BehaviourStatic
+	}
+
+	return report
+}
+
+// exclusiveShare is the fraction of a country's samples that contain an IP no other country received
+func exclusiveShare(code string, c *countrySamples, ipCountries map[string]map[string]bool) float64 {
+	var exclusive uint
+	for set, count := range c.answerSets {
+		for _, ip := range splitAnswerSet(set) {
+			if len(ipCountries[ip]) == 1 && ipCountries[ip][code] {
+				exclusive += count
+				break
+			}
+		}
+	}
+	return float64(exclusive) / float64(c.samples)
+}
+
+func dominantShare(c *countrySamples) float64 {
+	var highest uint
+	for _, count := range c.answerSets {
+		if count > highest {
+			highest = count
+		}
+	}
+	return float64(highest) / float64(c.samples)
+}
+
+func rotationBehaviour(c *countrySamples) string {
+	imbalance := dominantShare(c) * float64(len(c.answerSets))
+	if imbalance >= weightedImbalance {
+		return BehaviourWeighted
+	}
+	return BehaviourRoundRobin
+}
+
+func splitAnswerSet(set string) []string {
+	if set == "" {
+		return nil
+	}
+	return strings.Split(set, ",")
+}
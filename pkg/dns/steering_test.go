@@ -0,0 +1,81 @@
+package dns
+
+import (
+	"testing"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+func TestClassifySteering(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        []utils.DNSObservation
+		expected     string
+		countryCodes map[string]string
+	}{
+		{
+			name:     "No observation",
+			input:    nil,
+			expected: BehaviourUnknown,
+		},
+		{
+			name: "Same answer everywhere",
+			input: []utils.DNSObservation{
+				{CountryCode: "fr", Samples: 5, AnswerSets: map[string]uint{"192.0.2.1": 5}},
+				{CountryCode: "us", Samples: 5, AnswerSets: map[string]uint{"192.0.2.1": 5}},
+			},
+			expected:     BehaviourStatic,
+			countryCodes: map[string]string{"fr": BehaviourStatic, "us": BehaviourStatic},
+		},
+		{
+			name: "Rotated records",
+			input: []utils.DNSObservation{
+				{CountryCode: "fr", Samples: 4, AnswerSets: map[string]uint{"192.0.2.1,192.0.2.2": 2, "192.0.2.2,192.0.2.1": 2}},
+				{CountryCode: "us", Samples: 4, AnswerSets: map[string]uint{"192.0.2.1,192.0.2.2": 2, "192.0.2.2,192.0.2.1": 2}},
+			},
+			expected:     BehaviourRoundRobin,
+			countryCodes: map[string]string{"fr": BehaviourRoundRobin, "us": BehaviourRoundRobin},
+		},
+		{
+			name: "Weighted rotation",
+			input: []utils.DNSObservation{
+				{CountryCode: "fr", Samples: 10, AnswerSets: map[string]uint{"192.0.2.1": 9, "192.0.2.2": 1}},
+				{CountryCode: "us", Samples: 10, AnswerSets: map[string]uint{"192.0.2.1": 8, "192.0.2.2": 2}},
+			},
+			expected:     BehaviourWeighted,
+			countryCodes: map[string]string{"fr": BehaviourWeighted, "us": BehaviourWeighted},
+		},
+		{
+			name: "Answer depends on country",
+			input: []utils.DNSObservation{
+				{CountryCode: "fr", Samples: 5, AnswerSets: map[string]uint{"192.0.2.1": 5}},
+				{CountryCode: "us", Samples: 5, AnswerSets: map[string]uint{"198.51.100.1": 5}},
+			},
+			expected:     BehaviourGeo,
+			countryCodes: map[string]string{"fr": BehaviourGeo, "us": BehaviourGeo},
+		},
+		{
+			name: "Answer depends on country and rotates",
+			input: []utils.DNSObservation{
+				{CountryCode: "fr", Samples: 4, AnswerSets: map[string]uint{"192.0.2.1,192.0.2.2": 2, "192.0.2.2,192.0.2.1": 2}},
+				{CountryCode: "us", Samples: 4, AnswerSets: map[string]uint{"198.51.100.1": 4}},
+			},
+			expected:     BehaviourGeo + "+" + BehaviourRoundRobin,
+			countryCodes: map[string]string{"fr": BehaviourGeo, "us": BehaviourGeo},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := ClassifySteering(tt.input)
+			if report.Behaviour != tt.expected {
+				t.Errorf("ClassifySteering() got = %v, expected = %v", report.Behaviour, tt.expected)
+			}
+			for code, behaviour := range tt.countryCodes {
+				if report.Countries[code].Behaviour != behaviour {
+					t.Errorf("ClassifySteering() country %s got = %v, expected = %v", code, report.Countries[code].Behaviour, behaviour)
+				}
+			}
+		})
+	}
+}
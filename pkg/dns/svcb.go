@@ -0,0 +1,179 @@
+package dns
+
+import (
+	"encoding/binary"
+	"errors"
+	"log"
+	"net"
+	"strings"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// TypeHTTPS is the HTTPS resource record type (RFC 9460), not known by dnsmessage
+const TypeHTTPS dnsmessage.Type = 65
+
+// SvcParamKeys (RFC 9460 section 14.3.2)
+const (
+	svcParamMandatory     = 0
+	svcParamALPN          = 1
+	svcParamNoDefaultALPN = 2
+	svcParamPort          = 3
+	svcParamIPv4Hint      = 4
+	svcParamECH           = 5
+	svcParamIPv6Hint      = 6
+)
+
+var errShortSVCB = errors.New("truncated SVCB record")
+
+// queryHTTPSRecords asks the nameserver ip for the HTTPS records of host
+func queryHTTPSRecords(ip net.IP, host string) []utils.SVCBRecord {
+	msg, err := exchange(ip, host, TypeHTTPS)
+	if err != nil {
+		log.Printf("Error querying HTTPS records: %v\n", err)
+		return nil
+	}
+
+	var records []utils.SVCBRecord
+	for _, rr := range msg.Answers {
+		body, ok := rr.Body.(*dnsmessage.UnknownResource)
+		if !ok || rr.Header.Type != TypeHTTPS {
+			continue
+		}
+		record, err := parseSVCB(body.Data)
+		if err != nil {
+			log.Printf("Error parsing HTTPS record: %v\n", err)
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// parseSVCB decodes the RDATA of a SVCB or HTTPS record
+func parseSVCB(data []byte) (utils.SVCBRecord, error) {
+	var record utils.SVCBRecord
+
+	if len(data) < 2 {
+		return record, errShortSVCB
+	}
+	record.Priority = binary.BigEndian.Uint16(data)
+	data = data[2:]
+
+	// the target name is never compressed
+	var labels []string
+	for {
+		if len(data) < 1 {
+			return record, errShortSVCB
+		}
+		size := int(data[0])
+		data = data[1:]
+		if size == 0 {
+			break
+		}
+		if len(data) < size {
+			return record, errShortSVCB
+		}
+		labels = append(labels, string(data[:size]))
+		data = data[size:]
+	}
+	record.Target = strings.Join(labels, ".") + "."
+
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return record, errShortSVCB
+		}
+		key := binary.BigEndian.Uint16(data)
+		size := int(binary.BigEndian.Uint16(data[2:]))
+		data = data[4:]
+		if len(data) < size {
+			return record, errShortSVCB
+		}
+		value := data[:size]
+		data = data[size:]
+
+		switch key {
+		case svcParamALPN:
+			for len(value) > 0 {
+				size := int(value[0])
+				if len(value) < 1+size {
+					return record, errShortSVCB
+				}
+				record.ALPN = append(record.ALPN, string(value[1:1+size]))
+				value = value[1+size:]
+			}
+		case svcParamNoDefaultALPN:
+			record.NoDefaultALPN = true
+		case svcParamPort:
+			if len(value) != 2 {
+				return record, errShortSVCB
+			}
+			record.Port = binary.BigEndian.Uint16(value)
+		case svcParamIPv4Hint:
+			for ; len(value) >= net.IPv4len; value = value[net.IPv4len:] {
+				record.IPv4Hint = append(record.IPv4Hint, net.IP(value[:net.IPv4len]).String())
+			}
+		case svcParamIPv6Hint:
+			for ; len(value) >= net.IPv6len; value = value[net.IPv6len:] {
+				record.IPv6Hint = append(record.IPv6Hint, net.IP(value[:net.IPv6len]).String())
+			}
+		case svcParamECH:
+			record.ECH = append([]byte(nil), value...)
+		case svcParamMandatory:
+			// every key we read is optional for us, nothing to enforce
+		}
+	}
+
+	return record, nil
+}
+
+// HTTPSHints returns the IPv4 hints and the protocols advertised by the HTTPS records
+// the nameserver ip gave from countryCode
+func HTTPSHints(res *utils.GeoIP, countryCode string, ip net.IP) ([]string, []string) {
+	var hints, alpn []string
+	seenHint := make(map[string]bool)
+	seenALPN := make(map[string]bool)
+
+	for _, obs := range res.Observations {
+		if obs.CountryCode != countryCode || !obs.Server.Equal(ip) {
+			continue
+		}
+		for _, record := range obs.HTTPS {
+			protocols := append([]string(nil), record.ALPN...)
+			if !record.NoDefaultALPN {
+				protocols = append(protocols, "http/1.1")
+			}
+			for _, proto := range protocols {
+				if !seenALPN[proto] {
+					seenALPN[proto] = true
+					alpn = append(alpn, proto)
+				}
+			}
+			for _, hint := range record.IPv4Hint {
+				if !seenHint[hint] {
+					seenHint[hint] = true
+					hints = append(hints, hint)
+				}
+			}
+		}
+	}
+
+	return hints, alpn
+}
+
+// ECHConfig returns the first ECH config list advertised to countryCode by the nameserver IP, if any
+func ECHConfig(res *utils.GeoIP, countryCode string, ip net.IP) []byte {
+	for _, obs := range res.Observations {
+		if obs.CountryCode != countryCode || !obs.Server.Equal(ip) {
+			continue
+		}
+		for _, record := range obs.HTTPS {
+			if len(record.ECH) > 0 {
+				return record.ECH
+			}
+		}
+	}
+	return nil
+}
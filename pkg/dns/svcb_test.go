@@ -0,0 +1,59 @@
+package dns
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+func TestParseSVCB(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected utils.SVCBRecord
+		hasError bool
+	}{
+		{
+			name:     "Alias form",
+			input:    []byte{0x00, 0x00, 0x03, 'c', 'd', 'n', 0x00},
+			expected: utils.SVCBRecord{Priority: 0, Target: "cdn."},
+		},
+		{
+			name: "Service form with alpn, port and hints",
+			input: []byte{
+				0x00, 0x01, 0x00,
+				0x00, 0x01, 0x00, 0x06, 0x02, 'h', '2', 0x02, 'h', '3',
+				0x00, 0x03, 0x00, 0x02, 0x20, 0xfb,
+				0x00, 0x04, 0x00, 0x08, 192, 0, 2, 1, 192, 0, 2, 2,
+				0x00, 0x05, 0x00, 0x02, 0xfe, 0x0d,
+			},
+			expected: utils.SVCBRecord{
+				Priority: 1,
+				Target:   ".",
+				ALPN:     []string{"h2", "h3"},
+				Port:     8443,
+				IPv4Hint: []string{"192.0.2.1", "192.0.2.2"},
+				ECH:      []byte{0xfe, 0x0d},
+			},
+		},
+		{
+			name:     "Truncated parameter",
+			input:    []byte{0x00, 0x01, 0x00, 0x00, 0x01, 0x00, 0x06, 0x02, 'h'},
+			hasError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, err := parseSVCB(tt.input)
+			if (err != nil) != tt.hasError {
+				t.Errorf("parseSVCB() error = %v, expected error = %v", err, tt.hasError)
+				return
+			}
+			if !tt.hasError && !reflect.DeepEqual(record, tt.expected) {
+				t.Errorf("parseSVCB() got = %+v, expected = %+v", record, tt.expected)
+			}
+		})
+	}
+}
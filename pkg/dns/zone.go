@@ -0,0 +1,187 @@
+package dns
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"sort"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// CheckZoneConsistency fetches the SOA serial and the target answers from every
+// nameserver IP, IPv6 included, and reports the secondaries that are out of sync
+func CheckZoneConsistency(res *utils.GeoIP, countryCode string) utils.ZoneReport {
+	report := utils.ZoneReport{
+		CountryCode: countryCode,
+		Zone:        res.Resource.Zone,
+	}
+
+	for _, ns := range res.Resource.Nameservers {
+		if err := GetIPsNameserver(&ns); err != nil {
+			log.Printf("Error getting IPs for nameserver: %v\n", err)
+			continue
+		}
+
+		for _, ip := range ns.IPs {
+			report.Servers = append(report.Servers, queryZoneServer(ns.Host.Host, ip, res.Resource.Zone, res.Resource.CnameHost))
+		}
+	}
+
+	analyseZone(&report)
+	return report
+}
+
+func queryZoneServer(nameserver string, ip net.IP, zone, host string) utils.ZoneServer {
+	server := utils.ZoneServer{Nameserver: nameserver, IP: ip}
+
+	msg, err := exchange(ip, zone, dnsmessage.TypeSOA)
+	if err != nil {
+		server.Error = err.Error()
+		return server
+	}
+	if msg.RCode != dnsmessage.RCodeSuccess {
+		server.Error = fmt.Sprintf("SOA query answered %s", msg.RCode)
+		return server
+	}
+	found := false
+	for _, rr := range msg.Answers {
+		if soa, ok := rr.Body.(*dnsmessage.SOAResource); ok {
+			server.Serial = soa.Serial
+			found = true
+			break
+		}
+	}
+	if !found {
+		server.Error = "no SOA in the answer, lame delegation"
+		return server
+	}
+
+	for _, qtype := range []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA} {
+		msg, err := exchange(ip, host, qtype)
+		if err != nil {
+			server.Error = err.Error()
+			return server
+		}
+		// NXDOMAIN is an answer, it is compared like the others
+		if msg.RCode != dnsmessage.RCodeSuccess && msg.RCode != dnsmessage.RCodeNameError {
+			server.Error = fmt.Sprintf("%s query answered %s", qtype, msg.RCode)
+			return server
+		}
+		server.Answers = append(server.Answers, answerIPs(msg)...)
+	}
+	sort.Strings(server.Answers)
+
+	return server
+}
+
+// analyseZone flags the servers whose serial is behind the newest one and the servers whose answers
+// differ from the ones given by most servers. Servers sharing at least one address agree, round-robin
+// nameservers answer different subsets of the same pool
+func analyseZone(report *utils.ZoneReport) {
+	var newest uint32
+	found := false
+	var answering []int
+	for i, server := range report.Servers {
+		if server.Error != "" {
+			continue
+		}
+		if !found || serialBehind(newest, server.Serial) {
+			newest = server.Serial
+			found = true
+		}
+		answering = append(answering, i)
+	}
+
+	groups := answerGroups(report.Servers, answering)
+	majority := -1
+	for i, group := range groups {
+		if majority == -1 || len(group.servers) > len(groups[majority].servers) {
+			majority = i
+		}
+	}
+
+	for i, server := range report.Servers {
+		name := fmt.Sprintf("%s (%s)", server.Nameserver, server.IP)
+		if server.Error != "" {
+			report.Notes = append(report.Notes, fmt.Sprintf("%s did not answer: %s", name, server.Error))
+			continue
+		}
+
+		if serialBehind(server.Serial, newest) {
+			report.Unsynchronised = append(report.Unsynchronised, name)
+			report.Notes = append(report.Notes, fmt.Sprintf(
+				"%s serial %d is behind %d: stale secondary, regional differences may come from it rather than geo-targeting",
+				name, server.Serial, newest))
+		}
+
+		if !groups[majority].servers[i] {
+			report.Diverging = append(report.Diverging, name)
+			report.Notes = append(report.Notes, fmt.Sprintf(
+				"%s answers %v while most nameservers answer %v",
+				name, server.Answers, groups[majority].answers))
+		}
+	}
+}
+
+// answerGroup is a set of servers whose answers overlap, directly or through other servers of the group
+type answerGroup struct {
+	servers map[int]bool
+	answers []string
+}
+
+// answerGroups merges the servers sharing an address, the servers answering nothing form their own group
+func answerGroups(servers []utils.ZoneServer, answering []int) []answerGroup {
+	var groups []answerGroup
+	for _, i := range answering {
+		merged := answerGroup{servers: map[int]bool{i: true}, answers: servers[i].Answers}
+		var rest []answerGroup
+		for _, group := range groups {
+			if sameGroup(group.answers, merged.answers) {
+				for j := range group.servers {
+					merged.servers[j] = true
+				}
+				merged.answers = union(merged.answers, group.answers)
+				continue
+			}
+			rest = append(rest, group)
+		}
+		groups = append(rest, merged)
+	}
+	return groups
+}
+
+func sameGroup(a, b []string) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	set := make(map[string]bool, len(a))
+	for _, answer := range a {
+		set[answer] = true
+	}
+	for _, answer := range b {
+		if set[answer] {
+			return true
+		}
+	}
+	return false
+}
+
+func union(a, b []string) []string {
+	set := make(map[string]bool, len(a)+len(b))
+	for _, answer := range append(append([]string{}, a...), b...) {
+		set[answer] = true
+	}
+	result := make([]string, 0, len(set))
+	for answer := range set {
+		result = append(result, answer)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// serialBehind compares SOA serials with the RFC 1982 serial number arithmetic
+func serialBehind(serial, than uint32) bool {
+	return serial != than && int32(than-serial) > 0
+}
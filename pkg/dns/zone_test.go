@@ -0,0 +1,107 @@
+package dns
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+func TestSerialBehind(t *testing.T) {
+	tests := []struct {
+		name     string
+		serial   uint32
+		than     uint32
+		expected bool
+	}{
+		{name: "Same serial", serial: 2024010101, than: 2024010101, expected: false},
+		{name: "Older serial", serial: 2024010101, than: 2024010102, expected: true},
+		{name: "Newer serial", serial: 2024010102, than: 2024010101, expected: false},
+		{name: "Wraparound newer", serial: 5, than: 4294967290, expected: false},
+		{name: "Wraparound older", serial: 4294967290, than: 5, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serialBehind(tt.serial, tt.than); got != tt.expected {
+				t.Errorf("serialBehind(%d, %d) = %v, expected %v", tt.serial, tt.than, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAnalyseZone(t *testing.T) {
+	server := func(name string, serial uint32, answers ...string) utils.ZoneServer {
+		return utils.ZoneServer{Nameserver: name, IP: net.ParseIP("192.0.2.53"), Serial: serial, Answers: answers}
+	}
+	failed := utils.ZoneServer{Nameserver: "ns4", IP: net.ParseIP("192.0.2.53"), Error: "SOA query answered RCodeRefused"}
+
+	tests := []struct {
+		name           string
+		servers        []utils.ZoneServer
+		unsynchronised []string
+		diverging      []string
+	}{
+		{
+			name: "In sync",
+			servers: []utils.ZoneServer{
+				server("ns1", 10, "198.51.100.1"),
+				server("ns2", 10, "198.51.100.1"),
+			},
+		},
+		{
+			name: "Stale secondary",
+			servers: []utils.ZoneServer{
+				server("ns1", 10, "198.51.100.1"),
+				server("ns2", 9, "198.51.100.1"),
+			},
+			unsynchronised: []string{"ns2 (192.0.2.53)"},
+		},
+		{
+			name: "Serial wraparound",
+			servers: []utils.ZoneServer{
+				server("ns1", 4294967290, "198.51.100.1"),
+				server("ns2", 3, "198.51.100.1"),
+			},
+			unsynchronised: []string{"ns1 (192.0.2.53)"},
+		},
+		{
+			name: "Refused is not stale",
+			servers: []utils.ZoneServer{
+				server("ns1", 10, "198.51.100.1"),
+				failed,
+			},
+		},
+		{
+			name: "Round-robin subsets agree",
+			servers: []utils.ZoneServer{
+				server("ns1", 10, "198.51.100.1", "198.51.100.2"),
+				server("ns2", 10, "198.51.100.2", "198.51.100.3"),
+				server("ns3", 10, "198.51.100.3"),
+			},
+		},
+		{
+			name: "Minority diverges",
+			servers: []utils.ZoneServer{
+				server("ns1", 10, "198.51.100.1"),
+				server("ns2", 10, "198.51.100.1"),
+				server("ns3", 10, "203.0.113.1"),
+			},
+			diverging: []string{"ns3 (192.0.2.53)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := utils.ZoneReport{Servers: tt.servers}
+			analyseZone(&report)
+			if !reflect.DeepEqual(report.Unsynchronised, tt.unsynchronised) {
+				t.Errorf("analyseZone() unsynchronised = %v, expected %v", report.Unsynchronised, tt.unsynchronised)
+			}
+			if !reflect.DeepEqual(report.Diverging, tt.diverging) {
+				t.Errorf("analyseZone() diverging = %v, expected %v", report.Diverging, tt.diverging)
+			}
+		})
+	}
+}
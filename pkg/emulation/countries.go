@@ -0,0 +1,80 @@
+package emulation
+
+// Country is what a browser located in the country would report, the coordinates are the capital's
+type Country struct {
+	Timezone  string
+	Locale    string
+	Latitude  float64
+	Longitude float64
+}
+
+// countries is keyed by the lowercase ISO 3166-1 alpha-2 code, as listed by the VPN relays
+var countries = map[string]Country{
+	"ae": {"Asia/Dubai", "ar-AE", 24.4539, 54.3773},
+	"al": {"Europe/Tirane", "sq-AL", 41.3275, 19.8187},
+	"ar": {"America/Argentina/Buenos_Aires", "es-AR", -34.6037, -58.3816},
+	"at": {"Europe/Vienna", "de-AT", 48.2082, 16.3738},
+	"au": {"Australia/Sydney", "en-AU", -35.2809, 149.1300},
+	"ba": {"Europe/Sarajevo", "bs-BA", 43.8563, 18.4131},
+	"be": {"Europe/Brussels", "nl-BE", 50.8503, 4.3517},
+	"bg": {"Europe/Sofia", "bg-BG", 42.6977, 23.3219},
+	"br": {"America/Sao_Paulo", "pt-BR", -15.7939, -47.8828},
+	"ca": {"America/Toronto", "en-CA", 45.4215, -75.6972},
+	"ch": {"Europe/Zurich", "de-CH", 46.9480, 7.4474},
+	"cl": {"America/Santiago", "es-CL", -33.4489, -70.6693},
+	"co": {"America/Bogota", "es-CO", 4.7110, -74.0721},
+	"cy": {"Asia/Nicosia", "el-CY", 35.1856, 33.3823},
+	"cz": {"Europe/Prague", "cs-CZ", 50.0755, 14.4378},
+	"de": {"Europe/Berlin", "de-DE", 52.5200, 13.4050},
+	"dk": {"Europe/Copenhagen", "da-DK", 55.6761, 12.5683},
+	"ec": {"America/Guayaquil", "es-EC", -0.1807, -78.4678},
+	"ee": {"Europe/Tallinn", "et-EE", 59.4370, 24.7536},
+	"es": {"Europe/Madrid", "es-ES", 40.4168, -3.7038},
+	"fi": {"Europe/Helsinki", "fi-FI", 60.1699, 24.9384},
+	"fr": {"Europe/Paris", "fr-FR", 48.8566, 2.3522},
+	"gb": {"Europe/London", "en-GB", 51.5074, -0.1278},
+	"ge": {"Asia/Tbilisi", "ka-GE", 41.7151, 44.8271},
+	"gr": {"Europe/Athens", "el-GR", 37.9838, 23.7275},
+	"hk": {"Asia/Hong_Kong", "zh-HK", 22.3193, 114.1694},
+	"hr": {"Europe/Zagreb", "hr-HR", 45.8150, 15.9819},
+	"hu": {"Europe/Budapest", "hu-HU", 47.4979, 19.0402},
+	"id": {"Asia/Jakarta", "id-ID", -6.2088, 106.8456},
+	"ie": {"Europe/Dublin", "en-IE", 53.3498, -6.2603},
+	"il": {"Asia/Jerusalem", "he-IL", 31.7683, 35.2137},
+	"in": {"Asia/Kolkata", "hi-IN", 28.6139, 77.2090},
+	"is": {"Atlantic/Reykjavik", "is-IS", 64.1466, -21.9426},
+	"it": {"Europe/Rome", "it-IT", 41.9028, 12.4964},
+	"jp": {"Asia/Tokyo", "ja-JP", 35.6762, 139.6503},
+	"kr": {"Asia/Seoul", "ko-KR", 37.5665, 126.9780},
+	"lt": {"Europe/Vilnius", "lt-LT", 54.6872, 25.2797},
+	"lu": {"Europe/Luxembourg", "fr-LU", 49.6116, 6.1319},
+	"lv": {"Europe/Riga", "lv-LV", 56.9496, 24.1052},
+	"md": {"Europe/Chisinau", "ro-MD", 47.0105, 28.8638},
+	"me": {"Europe/Podgorica", "sr-ME", 42.4304, 19.2594},
+	"mk": {"Europe/Skopje", "mk-MK", 41.9981, 21.4254},
+	"mt": {"Europe/Malta", "mt-MT", 35.8989, 14.5146},
+	"mx": {"America/Mexico_City", "es-MX", 19.4326, -99.1332},
+	"my": {"Asia/Kuala_Lumpur", "ms-MY", 3.1390, 101.6869},
+	"ng": {"Africa/Lagos", "en-NG", 9.0765, 7.3986},
+	"nl": {"Europe/Amsterdam", "nl-NL", 52.3676, 4.9041},
+	"no": {"Europe/Oslo", "nb-NO", 59.9139, 10.7522},
+	"nz": {"Pacific/Auckland", "en-NZ", -41.2866, 174.7756},
+	"pe": {"America/Lima", "es-PE", -12.0464, -77.0428},
+	"ph": {"Asia/Manila", "fil-PH", 14.5995, 120.9842},
+	"pl": {"Europe/Warsaw", "pl-PL", 52.2297, 21.0122},
+	"pt": {"Europe/Lisbon", "pt-PT", 38.7223, -9.1393},
+	"ro": {"Europe/Bucharest", "ro-RO", 44.4268, 26.1025},
+	"rs": {"Europe/Belgrade", "sr-RS", 44.7866, 20.4489},
+	"ru": {"Europe/Moscow", "ru-RU", 55.7558, 37.6173},
+	"se": {"Europe/Stockholm", "sv-SE", 59.3293, 18.0686},
+	"sg": {"Asia/Singapore", "en-SG", 1.3521, 103.8198},
+	"si": {"Europe/Ljubljana", "sl-SI", 46.0569, 14.5058},
+	"sk": {"Europe/Bratislava", "sk-SK", 48.1486, 17.1077},
+	"th": {"Asia/Bangkok", "th-TH", 13.7563, 100.5018},
+	"tr": {"Europe/Istanbul", "tr-TR", 39.9334, 32.8597},
+	"tw": {"Asia/Taipei", "zh-TW", 25.0330, 121.5654},
+	"ua": {"Europe/Kyiv", "uk-UA", 50.4501, 30.5234},
+	"us": {"America/New_York", "en-US", 38.9072, -77.0369},
+	"vn": {"Asia/Ho_Chi_Minh", "vi-VN", 21.0278, 105.8342},
+	"za": {"Africa/Johannesburg", "en-ZA", -25.7479, 28.2293},
+}
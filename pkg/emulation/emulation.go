@@ -0,0 +1,145 @@
+package emulation
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	ModeCountry = "country"
+	ModeNone    = "none"
+
+	SettingTimezone    = "timezone"
+	SettingLocale      = "locale"
+	SettingGeolocation = "geolocation"
+)
+
+// Mode tells which country each emulated setting is taken from, an empty country is the vantage country
+type Mode struct {
+	Disabled    bool
+	Timezone    string
+	Locale      string
+	Geolocation string
+}
+
+// Settings is what the browser reports, every setting records the country it comes from.
+// An empty setting is left to the browser
+type Settings struct {
+	Timezone           string
+	TimezoneCountry    string
+	Locale             string
+	AcceptLanguage     string
+	LocaleCountry      string
+	Latitude           float64
+	Longitude          float64
+	GeolocationCountry string
+}
+
+// Lookup returns the settings of a country of the table
+func Lookup(code string) (Country, bool) {
+	country, ok := countries[strings.ToLower(code)]
+	return country, ok
+}
+
+// ParseMode parses the emulation flag: "country" (or empty) follows the vantage country, "none" disables
+// the emulation, a country code takes every setting from that country and a comma separated list such as
+// "timezone=jp,geolocation=us" only moves these settings, to run mismatch experiments
+func ParseMode(value string) (Mode, error) {
+	var mode Mode
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	switch {
+	case value == "" || value == ModeCountry:
+		return mode, nil
+	case value == ModeNone:
+		return Mode{Disabled: true}, nil
+	case !strings.Contains(value, "="):
+		if _, ok := Lookup(value); !ok {
+			return mode, fmt.Errorf("unknown country %q", value)
+		}
+		return Mode{Timezone: value, Locale: value, Geolocation: value}, nil
+	}
+
+	for _, part := range strings.Split(value, ",") {
+		setting, code, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return mode, fmt.Errorf("expected setting=country, got %q", part)
+		}
+		if _, known := Lookup(code); !known {
+			return mode, fmt.Errorf("unknown country %q", code)
+		}
+
+		switch setting {
+		case SettingTimezone:
+			mode.Timezone = code
+		case SettingLocale:
+			mode.Locale = code
+		case SettingGeolocation:
+			mode.Geolocation = code
+		default:
+			return mode, fmt.Errorf("unknown setting %q", setting)
+		}
+	}
+	return mode, nil
+}
+
+// Resolve returns the settings emulated from the vantage country, false when the emulation is disabled
+// or when no setting is left to emulate. A setting taken from a country missing from the table is left empty
+func (m Mode) Resolve(vantage string) (Settings, bool) {
+	var settings Settings
+	if m.Disabled {
+		return settings, false
+	}
+	pick := func(code string) string {
+		if code == "" {
+			return strings.ToLower(vantage)
+		}
+		return code
+	}
+
+	if code := pick(m.Timezone); code != "" {
+		if country, ok := Lookup(code); ok {
+			settings.Timezone, settings.TimezoneCountry = country.Timezone, code
+		}
+	}
+	if code := pick(m.Locale); code != "" {
+		if country, ok := Lookup(code); ok {
+			settings.Locale, settings.AcceptLanguage, settings.LocaleCountry = country.Locale, AcceptLanguage(country.Locale), code
+		}
+	}
+	if code := pick(m.Geolocation); code != "" {
+		if country, ok := Lookup(code); ok {
+			settings.Latitude, settings.Longitude, settings.GeolocationCountry = country.Latitude, country.Longitude, code
+		}
+	}
+	return settings, settings != Settings{}
+}
+
+// Mismatch lists the settings taken from another country than the vantage country
+func (s Settings) Mismatch(vantage string) []string {
+	vantage = strings.ToLower(vantage)
+	var settings []string
+	if s.TimezoneCountry != "" && s.TimezoneCountry != vantage {
+		settings = append(settings, SettingTimezone)
+	}
+	if s.LocaleCountry != "" && s.LocaleCountry != vantage {
+		settings = append(settings, SettingLocale)
+	}
+	if s.GeolocationCountry != "" && s.GeolocationCountry != vantage {
+		settings = append(settings, SettingGeolocation)
+	}
+	return settings
+}
+
+// AcceptLanguage builds the header a browser set to locale sends, e.g. "fr-FR,fr;q=0.9,en;q=0.8"
+func AcceptLanguage(locale string) string {
+	language, _, _ := strings.Cut(locale, "-")
+	header := locale
+	if language != locale {
+		header += "," + language + ";q=0.9"
+	}
+	if language != "en" {
+		header += ",en;q=0.8"
+	}
+	return header
+}
@@ -0,0 +1,99 @@
+package emulation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected Mode
+		wantErr  bool
+	}{
+		{name: "Default", value: "", expected: Mode{}},
+		{name: "Vantage country", value: "country", expected: Mode{}},
+		{name: "Disabled", value: "none", expected: Mode{Disabled: true}},
+		{name: "Other country", value: "JP", expected: Mode{Timezone: "jp", Locale: "jp", Geolocation: "jp"}},
+		{name: "Mismatch", value: "timezone=jp, geolocation=us", expected: Mode{Timezone: "jp", Geolocation: "us"}},
+		{name: "Unknown country", value: "xx", wantErr: true},
+		{name: "Unknown setting", value: "currency=jp", wantErr: true},
+		{name: "Missing country", value: "timezone", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode, err := ParseMode(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && mode != tt.expected {
+				t.Errorf("ParseMode() = %+v, expected %+v", mode, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     Mode
+		vantage  string
+		mismatch []string
+		timezone string
+		locale   string
+	}{
+		{name: "Vantage country", mode: Mode{}, vantage: "fr", timezone: "Europe/Paris", locale: "fr-FR"},
+		{name: "Timezone mismatch", mode: Mode{Timezone: "jp"}, vantage: "fr", mismatch: []string{SettingTimezone}, timezone: "Asia/Tokyo", locale: "fr-FR"},
+		{name: "Unknown vantage", mode: Mode{Locale: "de"}, vantage: "aq", mismatch: []string{SettingLocale}, locale: "de-DE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings, ok := tt.mode.Resolve(tt.vantage)
+			if !ok {
+				t.Fatalf("Resolve() disabled")
+			}
+			if settings.Timezone != tt.timezone || settings.Locale != tt.locale {
+				t.Errorf("Resolve() = %+v, expected timezone %q locale %q", settings, tt.timezone, tt.locale)
+			}
+			if mismatch := settings.Mismatch(tt.vantage); !reflect.DeepEqual(mismatch, tt.mismatch) {
+				t.Errorf("Mismatch() = %v, expected %v", mismatch, tt.mismatch)
+			}
+		})
+	}
+
+	if _, ok := (Mode{Disabled: true}).Resolve("fr"); ok {
+		t.Errorf("Resolve() of a disabled mode should not emulate")
+	}
+	if settings, ok := (Mode{}).Resolve("aq"); ok {
+		t.Errorf("Resolve() of a country missing from the table = %+v, expected no emulation", settings)
+	}
+}
+
+func TestCountries(t *testing.T) {
+	for code, country := range countries {
+		if _, err := time.LoadLocation(country.Timezone); err != nil {
+			t.Errorf("%s: invalid timezone %q: %v", code, country.Timezone, err)
+		}
+		if !strings.HasSuffix(country.Locale, "-"+strings.ToUpper(code)) {
+			t.Errorf("%s: locale %q is not of the country", code, country.Locale)
+		}
+	}
+}
+
+func TestAcceptLanguage(t *testing.T) {
+	tests := map[string]string{
+		"fr-FR": "fr-FR,fr;q=0.9,en;q=0.8",
+		"en-GB": "en-GB,en;q=0.9",
+		"ja":    "ja,en;q=0.8",
+	}
+	for locale, expected := range tests {
+		if got := AcceptLanguage(locale); got != expected {
+			t.Errorf("AcceptLanguage(%q) = %q, expected %q", locale, got, expected)
+		}
+	}
+}
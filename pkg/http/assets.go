@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/page"
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+// fetchAssets hashes up to limit subresources of a document, the endpoint host goes
+// through the pinned destination of the client, other hosts are resolved as usual
+func fetchAssets(ctx context.Context, client *http.Client, base *url.URL, resources []page.Resource, profile utils.Profile, limit uint) []utils.Asset {
+	// assets may redirect to a CDN, unlike the endpoint their chain is not recorded
+	client.CheckRedirect = nil
+
+	var assets []utils.Asset
+	seen := make(map[string]bool)
+	for _, resource := range resources {
+		if uint(len(assets)) >= limit {
+			break
+		}
+		u, err := base.Parse(resource.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+		u.Fragment = ""
+		if seen[u.String()] {
+			continue
+		}
+		seen[u.String()] = true
+
+		asset := utils.Asset{URL: u.String(), Kind: resource.Kind}
+		if err := fetchAsset(ctx, client, profile, &asset); err != nil {
+			log.Printf("Error fetching asset %s: %v\n", asset.URL, err)
+			asset.Error = err.Error()
+		}
+		assets = append(assets, asset)
+	}
+	return assets
+}
+
+func fetchAsset(ctx context.Context, client *http.Client, profile utils.Profile, asset *utils.Asset) error {
+	ctx, cancel := context.WithTimeout(ctx, *utils.RequestTimeout)
+	defer cancel()
+
+	req, err := newProfileRequest(ctx, http.MethodGet, asset.URL, "", profile)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	asset.StatusCode = resp.StatusCode
+	asset.ContentType = resp.Header.Get("Content-Type")
+	hasher := utils.NewHash()
+	size, err := io.Copy(hasher, resp.Body)
+	if err != nil {
+		return err
+	}
+	asset.Hash = hasher.Sum(nil)
+	asset.Size = size
+	return nil
+}
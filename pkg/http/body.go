@@ -0,0 +1,75 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+// retryBackoff is the wait before the first retry, doubled for each one, the tests shorten it
+var retryBackoff = 500 * time.Millisecond
+
+// readBody hashes the whole body while it streams and keeps at most max bytes of it,
+// Hash and BodySize describe the full body even when it is truncated
+func readBody(r io.Reader, max int64, analyze *utils.Analyze) ([]byte, error) {
+	hasher := utils.NewHash()
+	var kept bytes.Buffer
+
+	size, err := io.Copy(io.MultiWriter(hasher, &limitedWriter{w: &kept, n: max}), r)
+	analyze.BodySize = size
+	analyze.Truncated = size > max
+	if err != nil {
+		return nil, err
+	}
+
+	analyze.Hash = hasher.Sum(nil)
+	return kept.Bytes(), nil
+}
+
+// limitedWriter drops what goes beyond n bytes without failing the copy
+type limitedWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.n > 0 {
+		keep := p
+		if int64(len(keep)) > l.n {
+			keep = keep[:l.n]
+		}
+		written, err := l.w.Write(keep)
+		l.n -= int64(written)
+		if err != nil {
+			return written, err
+		}
+	}
+	return len(p), nil
+}
+
+// isTransient tells if an attempt may succeed when retried, an answer from
+// the server is never retried, neither is anything once the scan is cancelled
+func isTransient(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, io.EOF),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.ECONNABORTED):
+		return true
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return true
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/normalize"
+	"github.com/OnsagerHe/geoip-detector/pkg/page"
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+// Crawl follows the same-site links of the endpoint breadth first through the destination of analyze,
+// up to depth links away and at most budget pages. Page hashes are computed after normalization
+func Crawl(ctx context.Context, resource *utils.EndpointMetadata, analyze *utils.Analyze, depth, budget uint) {
+	client, _, _, err := newClient(resource, analyze)
+	if err != nil {
+		log.Printf("Error creating client: %v\n", err)
+		return
+	}
+
+	type item struct {
+		url   string
+		depth uint
+	}
+	queue := []item{{url: resource.Endpoint}}
+	seen := map[string]bool{resource.Endpoint: true}
+	analyze.Pages = nil
+
+	for len(queue) > 0 && uint(len(analyze.Pages)) < budget && ctx.Err() == nil {
+		current := queue[0]
+		queue = queue[1:]
+
+		visited := utils.Page{URL: current.url, Depth: current.depth}
+		links, err := crawlPage(ctx, client, resource, analyze.Profile, &visited)
+		if err != nil {
+			log.Printf("Error crawling %s: %v\n", current.url, err)
+		}
+		analyze.Pages = append(analyze.Pages, visited)
+
+		if current.depth >= depth {
+			continue
+		}
+		for _, link := range links {
+			if !seen[link] {
+				seen[link] = true
+				queue = append(queue, item{url: link, depth: current.depth + 1})
+			}
+		}
+	}
+}
+
+// crawlPage fetches one page and returns its same-site links
+func crawlPage(ctx context.Context, client *http.Client, resource *utils.EndpointMetadata, profile utils.Profile, visited *utils.Page) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, *utils.RequestTimeout)
+	defer cancel()
+
+	req, err := newProfileRequest(ctx, http.MethodGet, visited.URL, "", profile)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	visited.StatusCode = resp.StatusCode
+	// hashed like the endpoint itself: the whole stream, or the normalized kept part
+	var read utils.Analyze
+	body, err := readBody(resp.Body, *utils.MaxBody, &read)
+	if err != nil {
+		return nil, err
+	}
+	visited.Hash = read.Hash
+
+	if resource.Normalize != nil {
+		if normalized, err := normalize.Apply(resource.Normalize, body); err != nil {
+			log.Printf("Error normalizing %s: %v\n", visited.URL, err)
+		} else {
+			visited.Hash = utils.HashByte(normalized)
+		}
+	}
+
+	if !strings.Contains(resp.Header.Get("Content-Type"), "html") {
+		return nil, nil
+	}
+	doc, err := page.Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	return sameSiteLinks(resp.Request.URL, resource.Host, page.Links(doc)), nil
+}
+
+// sameSiteLinks resolves the links against base and keeps the http(s) ones of host, www. or not, without fragment
+func sameSiteLinks(base *url.URL, host string, links []string) []string {
+	site := strings.TrimPrefix(strings.ToLower(host), "www.")
+
+	var kept []string
+	for _, link := range links {
+		u, err := base.Parse(link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+		if strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.") != site {
+			continue
+		}
+		u.Fragment = ""
+		u.RawFragment = ""
+		kept = append(kept, u.String())
+	}
+	return kept
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"log"
+
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/cdp"
+	cdpemulation "github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/chromedp"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/emulation"
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+// emulate makes the tab report the timezone, locale and position of the analyze country, or of the countries
+// chosen by mode, before anything is loaded. The settings are recorded on the analyze
+func emulate(ctx context.Context, mode emulation.Mode, analyze *utils.Analyze) error {
+	if _, known := emulation.Lookup(analyze.CountryCode); !known && !mode.Disabled {
+		log.Printf("Error emulating the browser: country %s is not in the emulation table\n", analyze.CountryCode)
+	}
+	settings, ok := mode.Resolve(analyze.CountryCode)
+	if !ok {
+		return nil
+	}
+	analyze.Emulation = &settings
+
+	return chromedp.Run(ctx, emulationTasks(settings))
+}
+
+func emulationTasks(settings emulation.Settings) chromedp.Tasks {
+	var tasks chromedp.Tasks
+	if settings.Timezone != "" {
+		tasks = append(tasks, cdpemulation.SetTimezoneOverride(settings.Timezone))
+	}
+	if settings.Locale != "" {
+		tasks = append(tasks,
+			cdpemulation.SetLocaleOverride().WithLocale(settings.Locale),
+			// the user agent is kept, the override is the only way to change navigator.languages with the header
+			chromedp.ActionFunc(func(ctx context.Context) error {
+				_, _, _, userAgent, _, err := browser.GetVersion().Do(ctx)
+				if err != nil {
+					return err
+				}
+				return cdpemulation.SetUserAgentOverride(userAgent).WithAcceptLanguage(settings.AcceptLanguage).Do(ctx)
+			}),
+		)
+	}
+	if settings.GeolocationCountry != "" {
+		tasks = append(tasks,
+			// permissions are granted by the browser to the incognito context of the tab
+			chromedp.ActionFunc(func(ctx context.Context) error {
+				c := chromedp.FromContext(ctx)
+				return browser.GrantPermissions([]browser.PermissionType{browser.PermissionTypeGeolocation}).
+					WithBrowserContextID(c.BrowserContextID).
+					Do(cdp.WithExecutor(ctx, c.Browser))
+			}),
+			cdpemulation.SetGeolocationOverride().WithLatitude(settings.Latitude).WithLongitude(settings.Longitude).WithAccuracy(100),
+		)
+	}
+	return tasks
+}
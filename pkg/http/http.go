@@ -2,37 +2,37 @@ package http
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"github.com/chromedp/chromedp"
-	"io"
 	"log"
 	"net"
 	"net/http"
+	"net/http/cookiejar"
+	"net/netip"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/OnsagerHe/geoip-detector/pkg/classifier"
+	"github.com/OnsagerHe/geoip-detector/pkg/emulation"
+	"github.com/OnsagerHe/geoip-detector/pkg/normalize"
+	"github.com/OnsagerHe/geoip-detector/pkg/page"
+	"github.com/OnsagerHe/geoip-detector/pkg/similarity"
 	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+	"golang.org/x/net/idna"
 )
 
-func getDomainFromURL(resource *utils.EndpointMetadata) error {
-	u, err := url.Parse(resource.Endpoint)
-	if err != nil {
-		return err
-	}
-	resource.Host = u.Hostname()
-	return nil
-}
-
-// customDialer creates a custom dialer that maps the domain to a specific IP address
+// customDialer creates a custom dialer that maps the domain and port to a specific IP address
 func customDialer(domain, ip, port string) func(ctx context.Context, network, address string) (net.Conn, error) {
 	return func(ctx context.Context, network, address string) (net.Conn, error) {
-		if address == domain+port {
-			address = ip + port
-		}
+		address = pinAddress(domain, ip, port, address)
 		dialer := &net.Dialer{
 			Timeout: 5 * time.Second,
 		}
@@ -40,14 +40,16 @@ func customDialer(domain, ip, port string) func(ctx context.Context, network, ad
 	}
 }
 
-func InitHTTPInformation(resource *utils.EndpointMetadata) error {
-	err := parseHTTP(resource)
-	if err != nil {
-		log.Printf("Error: %v\n", err)
-		return err
+// pinAddress replaces the address of domain on port by ip, other addresses are left alone
+func pinAddress(domain, ip, port, address string) string {
+	if host, p, err := net.SplitHostPort(address); err == nil && strings.EqualFold(host, domain) && p == port {
+		return net.JoinHostPort(ip, port)
 	}
+	return address
+}
 
-	err = getDomainFromURL(resource)
+func InitHTTPInformation(resource *utils.EndpointMetadata) error {
+	err := parseHTTP(resource)
 	if err != nil {
 		log.Printf("Error: %v\n", err)
 		return err
@@ -56,24 +58,54 @@ func InitHTTPInformation(resource *utils.EndpointMetadata) error {
 	return nil
 }
 
-func TakeScreenshot(res *utils.GeoIP) {
+func TakeScreenshot(ctx context.Context, pool *BrowserPool, res *utils.GeoIP) {
+	analyzes := make([]*utils.Analyze, len(res.Analyzes))
 	for i := range res.Analyzes {
-		err := takeScreenshot(&res.Resource, &(res.Analyzes)[i])
-		if err != nil {
-			log.Println("error", err)
-			return
-		}
+		analyzes[i] = &res.Analyzes[i]
 	}
+	TakeScreenshotByCountryCode(ctx, pool, res, analyzes)
 }
 
-func TakeScreenshotByCountryCode(res *utils.GeoIP, analyzes []*utils.Analyze) {
-	for i := range analyzes {
-		err := takeScreenshot(&res.Resource, (analyzes)[i])
-		if err != nil {
-			log.Println("error", err)
-			return
+// TakeScreenshotByCountryCode captures each destination of each country once concurrently, the pool caps how many
+// run at once. The browser ignores the profile and the protocol, the other analyzes of the destination share the capture
+func TakeScreenshotByCountryCode(ctx context.Context, pool *BrowserPool, res *utils.GeoIP, analyzes []*utils.Analyze) {
+	var wg sync.WaitGroup
+	for _, group := range byDestination(analyzes) {
+		wg.Add(1)
+		go func(group []*utils.Analyze) {
+			defer wg.Done()
+			if err := takeScreenshot(ctx, pool, &res.Resource, res.Emulation, group[0]); err != nil {
+				log.Println("error", err)
+			}
+			for _, analyze := range group[1:] {
+				analyze.Filename = group[0].Filename
+				analyze.Screenshot = group[0].Screenshot
+				analyze.Emulation = group[0].Emulation
+			}
+		}(group)
+	}
+	wg.Wait()
+}
+
+// byDestination groups the analyzes by country and destination IP, in the order they come
+func byDestination(analyzes []*utils.Analyze) [][]*utils.Analyze {
+	type destination struct {
+		country string
+		ip      string
+	}
+	index := make(map[destination]int)
+	var groups [][]*utils.Analyze
+	for _, analyze := range analyzes {
+		key := destination{analyze.CountryCode, analyze.IpDest}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
 		}
+		groups[i] = append(groups[i], analyze)
 	}
+	return groups
 }
 
 func fileExists(path string) bool {
@@ -107,24 +139,32 @@ func setBrowserBinaryPath() string {
 }
 
 // TakeScreenshot captures a screenshot of the given URL and saves it to the specified folder.
-func takeScreenshot(resource *utils.EndpointMetadata, analyze *utils.Analyze) error {
-	*utils.BrowserPath = setBrowserBinaryPath()
-	if *utils.BrowserPath == "" {
-		return fmt.Errorf("browser path unknown")
-	}
-
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.ExecPath(*utils.BrowserPath),
-		chromedp.Flag("headless", true),
-		chromedp.Flag("disable-gpu", true),
-		chromedp.Flag("new-instance", true),
-	)
-	allocCtx, cancel := chromedp.NewExecAllocator(context.Background(), opts...)
-	defer cancel()
+func takeScreenshot(ctx context.Context, pool *BrowserPool, resource *utils.EndpointMetadata, mode emulation.Mode, analyze *utils.Analyze) error {
+	proxy, err := startPinningProxy(resource, analyze)
+	if err != nil {
+		return fmt.Errorf("failed to start pinning proxy: %w", err)
+	}
+	defer proxy.Close()
+
+	tab, release, err := pool.Acquire(ctx, proxy.browserContext())
+	if err != nil {
+		return err
+	}
+	defer release()
 
-	ctx, cancel := chromedp.NewContext(allocCtx)
+	// the tab is attached with its own ctx, the event loop would stop with a timeout one
+	if err := chromedp.Run(tab); err != nil {
+		return fmt.Errorf("failed to open the tab: %w", err)
+	}
+
+	// a page that never becomes visible must not hold the slot
+	ctx, cancel := context.WithTimeout(tab, *utils.RequestTimeout)
 	defer cancel()
 
+	if err := emulate(ctx, mode, analyze); err != nil {
+		return fmt.Errorf("failed to emulate the country: %w", err)
+	}
+
 	var buf []byte
 	if err := chromedp.Run(ctx, fullScreenshot(resource.Endpoint, &buf)); err != nil {
 		return fmt.Errorf("failed to capture screenshot: %w", err)
@@ -134,9 +174,9 @@ func takeScreenshot(resource *utils.EndpointMetadata, analyze *utils.Analyze) er
 		return fmt.Errorf("failed to create folder: %w", err)
 	}
 
-	// TODO: maybe add encoded information to avoid filename to long
-	fileName := fmt.Sprintf("%s_%s_%x.%s", resource.Host, analyze.CountryCode, analyze.Hash, "png")
+	fileName := screenshotName(resource, analyze)
 	analyze.Filename = fileName
+	analyze.Screenshot = fileName
 	filePath := filepath.Join(*utils.FolderPath, fileName)
 	if err := os.WriteFile(filePath, buf, 0644); err != nil {
 		return fmt.Errorf("failed to save screenshot: %w", err)
@@ -145,6 +185,12 @@ func takeScreenshot(resource *utils.EndpointMetadata, analyze *utils.Analyze) er
 	return nil
 }
 
+// screenshotName tells the captures apart, the hash alone is shared by destinations serving the same bytes
+// and is empty for failed requests
+func screenshotName(resource *utils.EndpointMetadata, analyze *utils.Analyze) string {
+	return resource.ArtifactName("png", analyze.CountryCode, analyze.IpDest, analyze.Profile.Name, analyze.Protocol, fmt.Sprintf("%x", analyze.Hash))
+}
+
 // fullScreenshot is a helper function to capture a full-page screenshot.
 func fullScreenshot(urlstr string, res *[]byte) chromedp.Tasks {
 	return chromedp.Tasks{
@@ -163,15 +209,15 @@ func screenshotTasks(url string, res *[]byte) chromedp.Tasks {
 	}
 }
 
-func RequestEndpoints(res *utils.GeoIP) {
+func RequestEndpoints(ctx context.Context, res *utils.GeoIP) {
 	for i := range res.Analyzes {
-		RequestEndpoint(&res.Resource, &(res.Analyzes)[i])
+		RequestEndpoint(ctx, &res.Resource, &(res.Analyzes)[i])
 	}
 }
 
-func RequestSpecificEndpoints(res *utils.GeoIP, analyzes []*utils.Analyze) {
+func RequestSpecificEndpoints(ctx context.Context, res *utils.GeoIP, analyzes []*utils.Analyze) {
 	for i := range analyzes {
-		RequestEndpoint(&res.Resource, &*(analyzes)[i])
+		RequestEndpoint(ctx, &res.Resource, &*(analyzes)[i])
 	}
 }
 
@@ -184,65 +230,223 @@ func downloadContent(body []byte, path string) error {
 	return nil
 }
 
-func RequestEndpoint(resource *utils.EndpointMetadata, analyze *utils.Analyze) {
-	client := &http.Client{
-		Transport: &http.Transport{
-			DialContext: customDialer(resource.Host, analyze.IpDest, resource.Port),
+// newTransport pins the endpoint host to the analyze destination and offers
+// the protocols advertised by the HTTPS records, if any, unless the analyze forces one
+func newTransport(resource *utils.EndpointMetadata, analyze *utils.Analyze, verifier *certVerifier) http.RoundTripper {
+	transport := &http.Transport{
+		DialContext: customDialer(resource.Host, analyze.IpDest, resource.Port),
+		TLSClientConfig: &tls.Config{
+			// the chain is verified by the verifier so that regional or invalid certificates are still inspected
+			InsecureSkipVerify: true,
+			VerifyConnection:   verifier.verifyConnection,
 		},
 	}
 
-	req, err := http.NewRequest("GET", resource.Endpoint, nil)
+	// net/http does not speak h3, it is only recorded with the DNS observation. Without HTTPS record
+	// both are offered, net/http turns h2 off by itself with a custom dialer or TLS config
+	protocols := []string{"h2", "http/1.1"}
+	var advertised []string
+	for _, proto := range analyze.ALPN {
+		if proto == "h2" || proto == "http/1.1" {
+			advertised = append(advertised, proto)
+		}
+	}
+	if len(advertised) > 0 {
+		protocols = advertised
+	}
+	transport.TLSClientConfig.NextProtos = protocols
+	transport.ForceAttemptHTTP2 = slices.Contains(protocols, "h2")
+
+	return applyProbe(forceProtocol(transport, resource, analyze), transport, resource, analyze)
+}
+
+// newClient pins the endpoint host to the analyze destination with the cookies of its profile
+func newClient(resource *utils.EndpointMetadata, analyze *utils.Analyze) (*http.Client, *certVerifier, *timingTransport, error) {
+	jar, err := cookiejar.New(nil)
 	if err != nil {
-		log.Printf("Error creating request: %v\n", err)
-		return
+		return nil, nil, nil, fmt.Errorf("creating cookie jar: %w", err)
 	}
+	if endpoint, err := url.Parse(resource.Endpoint); err == nil {
+		var cookies []*http.Cookie
+		for name, value := range analyze.Profile.Cookies {
+			cookies = append(cookies, &http.Cookie{Name: name, Value: value})
+		}
+		jar.SetCookies(endpoint, cookies)
+	}
+
+	verifier := newCertVerifier()
+	timer := &timingTransport{base: newTransport(resource, analyze, verifier)}
+	client := &http.Client{
+		Transport: timer,
+		Jar:       jar,
+	}
+	return client, verifier, timer, nil
+}
 
-	resp, err := client.Do(req)
+// RequestEndpoint requests the endpoint through the destination of analyze, transient
+// errors are retried with backoff until the retries run out or ctx is done
+func RequestEndpoint(ctx context.Context, resource *utils.EndpointMetadata, analyze *utils.Analyze) {
+	initial := *analyze
+	for attempt := 1; ; attempt++ {
+		// nothing from a failed attempt is kept
+		*analyze = initial
+		analyze.Attempts = attempt
+
+		err := requestEndpoint(ctx, resource, analyze)
+		if err == nil {
+			return
+		}
+		analyze.Error = err.Error()
+
+		if attempt > int(*utils.Retries) || !isTransient(ctx, err) {
+			log.Printf("Error requesting %s (attempt %d): %v\n", analyze.IpDest, attempt, err)
+			return
+		}
+
+		wait := retryBackoff << (attempt - 1)
+		log.Printf("Transient error requesting %s (attempt %d), retrying in %s: %v\n", analyze.IpDest, attempt, wait, err)
+		select {
+		case <-ctx.Done():
+			analyze.Error = ctx.Err().Error()
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// requestEndpoint is one attempt, the redirect chain and the body share the request timeout
+func requestEndpoint(ctx context.Context, resource *utils.EndpointMetadata, analyze *utils.Analyze) error {
+	client, verifier, timer, err := newClient(resource, analyze)
 	if err != nil {
-		log.Printf("Error performing request: %v\n", err)
-		return
+		return err
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, *utils.RequestTimeout)
+	defer cancel()
+
+	analyze.Status = utils.StatusUnreachable
+	resp, hops, err := followRedirects(attemptCtx, client, resource.Endpoint, analyze.Profile, *utils.MaxRedirects)
+	analyze.Redirects = hops
+	analyze.RedirectClass = ClassifyRedirects(hops, *utils.MaxRedirects)
+	if err != nil {
+		return fmt.Errorf("performing request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
+	analyze.StatusCode = resp.StatusCode
+	analyze.Status = utils.StatusFromCode(resp.StatusCode)
+	analyze.Headers = resp.Header
+	analyze.ContentType = resp.Header.Get("Content-Type")
+	analyze.NegotiatedProtocol = resp.Proto
+	analyze.AltSvc = parseAltSvc(resp.Header.Values("Alt-Svc"))
+	analyze.HTTP3 = advertisesH3(analyze.AltSvc)
+	if resp.TLS != nil {
+		analyze.TLS = inspectTLS(resp.TLS, verifier.errorFor(resp.TLS.ServerName))
+	}
+
+	// block pages and error pages are kept as well, they are what differs between regions
+	body, err := readBody(resp.Body, *utils.MaxBody, analyze)
+	analyze.Timing = timer.timing(time.Now(), analyze.Resolution)
+	if err != nil {
+		return fmt.Errorf("reading the response body: %w", err)
+	}
+
+	if resource.Normalize != nil {
+		normalized, err := normalize.Apply(resource.Normalize, body)
 		if err != nil {
-			log.Printf("Error reading the response body: %v\n", err)
-			return
+			log.Printf("Error normalizing the response body: %v\n", err)
+			analyze.NormalizeError = err.Error()
+		} else {
+			analyze.NormalizedHash = utils.HashByte(normalized)
 		}
+	}
 
-		analyze.Hash = utils.HashByte(body)
-		analyze.Online = true
+	var title string
+	if doc, err := page.Parse(body); err != nil {
+		log.Printf("Error parsing the response body: %v\n", err)
+	} else {
+		content := page.Extract(doc)
+		title = content.Title
+		analyze.Fingerprint = similarity.Fingerprint(doc)
+		analyze.Content = content.Lines()
+		if *utils.Subresources > 0 {
+			analyze.Assets = fetchAssets(ctx, client, resp.Request.URL, page.Subresources(doc), analyze.Profile, *utils.Subresources)
+		}
+	}
 
-		if *utils.Source {
-			if err := os.MkdirAll(*utils.FolderPath, os.ModePerm); err != nil {
-				log.Printf("failed to create folder: %w", err)
-			}
+	analyze.Verdict = classifier.Classify(resource.Signatures, classifier.Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		Title:      title,
+	})
 
-			// TODO: maybe add encoded information to avoid filename to long
-			fileName := fmt.Sprintf("%s_%s_%x.%s", resource.Host, analyze.CountryCode, analyze.Hash, "html")
-			analyze.Filename = fileName
-			filePath := filepath.Join(*utils.FolderPath, fileName)
-			err = downloadContent(body, filePath)
-			if err != nil {
-				log.Printf("Error download body: %v\n", err)
-			}
+	if *utils.Source {
+		if err := os.MkdirAll(*utils.FolderPath, os.ModePerm); err != nil {
+			log.Printf("failed to create folder: %v", err)
+		}
+
+		fileName := resource.ArtifactName("html", analyze.CountryCode, fmt.Sprintf("%x", analyze.Hash))
+		analyze.Filename = fileName
+		filePath := filepath.Join(*utils.FolderPath, fileName)
+		err = downloadContent(body, filePath)
+		if err != nil {
+			log.Printf("Error download body: %v\n", err)
 		}
-	} else {
-		analyze.Online = false
 	}
+
+	return nil
 }
 
+// parseHTTP normalizes the endpoint: lower case scheme and host, IDN to punycode,
+// userinfo and default port removed from the URL, explicit port and host kept apart
 func parseHTTP(resource *utils.EndpointMetadata) error {
-	if strings.HasPrefix(resource.Endpoint, "https://") {
-		resource.Prefix = "https://"
-		resource.Port = ":443"
-	} else if strings.HasPrefix(resource.Endpoint, "http://") {
-		resource.Prefix = "http://"
-		resource.Port = ":80"
-	} else {
+	u, err := url.Parse(strings.TrimSpace(resource.Endpoint))
+	if err != nil {
+		return err
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
 		return errors.New("endpoint must be contain http:// or https://")
 	}
-	return nil
+	if u.Hostname() == "" {
+		return errors.New("endpoint has no host")
+	}
+
+	host := u.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if addr.Zone() != "" {
+			return fmt.Errorf("zoned IPv6 address %s is not supported", host)
+		}
+		host = addr.String()
+	} else {
+		host, err = idna.Lookup.ToASCII(strings.TrimSuffix(host, "."))
+		if err != nil {
+			return fmt.Errorf("invalid host %q: %w", u.Hostname(), err)
+		}
+	}
 
+	port := u.Port()
+	if port == "" {
+		port = utils.DefaultPort(scheme)
+	} else if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("invalid port %q", port)
+	}
+
+	u.Scheme = scheme
+	u.User = nil
+	u.Host = host
+	if strings.Contains(host, ":") {
+		u.Host = "[" + host + "]"
+	}
+	if port != utils.DefaultPort(scheme) {
+		u.Host = net.JoinHostPort(host, port)
+	}
+
+	resource.Endpoint = u.String()
+	resource.Scheme = scheme
+	resource.Host = host
+	resource.Port = port
+	return nil
 }
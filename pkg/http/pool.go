@@ -0,0 +1,128 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/chromedp/chromedp"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+// BrowserPool shares one headless browser between the captures, each capture gets
+// its own incognito browser context and at most workers captures run at once
+type BrowserPool struct {
+	mu           sync.Mutex
+	browser      context.Context
+	closeBrowser context.CancelFunc
+	slots        chan struct{}
+	// start and newTab are replaced by the tests, which run without a browser
+	start  func() (context.Context, context.CancelFunc, error)
+	newTab func(context.Context, ...chromedp.CreateBrowserContextOption) (context.Context, context.CancelFunc)
+}
+
+func NewBrowserPool(workers uint) *BrowserPool {
+	if workers == 0 {
+		workers = 1
+	}
+	return &BrowserPool{
+		slots: make(chan struct{}, workers),
+		start: startBrowser,
+		newTab: func(browser context.Context, options ...chromedp.CreateBrowserContextOption) (context.Context, context.CancelFunc) {
+			return chromedp.NewContext(browser, chromedp.WithNewBrowserContext(options...))
+		},
+	}
+}
+
+// Acquire waits for a free slot and returns an incognito tab, the browser is started
+// again if it is not running or has crashed. Cancelling ctx closes the tab, release
+// closes it and frees the slot
+func (p *BrowserPool) Acquire(ctx context.Context, options ...chromedp.CreateBrowserContextOption) (context.Context, context.CancelFunc, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	browser, err := p.running()
+	if err != nil {
+		<-p.slots
+		return nil, nil, err
+	}
+
+	tab, closeTab := p.newTab(browser, options...)
+	stop := context.AfterFunc(ctx, closeTab)
+	release := func() {
+		stop()
+		closeTab()
+		<-p.slots
+	}
+	return tab, release, nil
+}
+
+func (p *BrowserPool) running() (context.Context, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// the allocator cancels the browser context when the connection to the browser is lost
+	if p.browser != nil && p.browser.Err() == nil {
+		return p.browser, nil
+	}
+	if p.closeBrowser != nil {
+		p.closeBrowser()
+	}
+
+	browser, closeBrowser, err := p.start()
+	if err != nil {
+		p.browser, p.closeBrowser = nil, nil
+		return nil, err
+	}
+	p.browser, p.closeBrowser = browser, closeBrowser
+	return browser, nil
+}
+
+// Reset stops the browser, the next capture starts a new one. Called when the vantage
+// changes so that nothing cached by the browser, DNS included, leaks between countries
+func (p *BrowserPool) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closeBrowser != nil {
+		p.closeBrowser()
+	}
+	p.browser, p.closeBrowser = nil, nil
+}
+
+// Close stops the browser once the scan is over
+func (p *BrowserPool) Close() {
+	p.Reset()
+}
+
+// startBrowser launches a headless browser and waits for it to be ready
+func startBrowser() (context.Context, context.CancelFunc, error) {
+	*utils.BrowserPath = setBrowserBinaryPath()
+	if *utils.BrowserPath == "" {
+		return nil, nil, fmt.Errorf("browser path unknown")
+	}
+
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.ExecPath(*utils.BrowserPath),
+		chromedp.Flag("headless", true),
+		chromedp.Flag("disable-gpu", true),
+		chromedp.Flag("new-instance", true),
+	)
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), opts...)
+	browser, cancelBrowser := chromedp.NewContext(allocCtx)
+	closeBrowser := func() {
+		cancelBrowser()
+		cancelAlloc()
+	}
+
+	// the first run starts the browser process
+	if err := chromedp.Run(browser); err != nil {
+		closeBrowser()
+		return nil, nil, fmt.Errorf("failed to start browser: %w", err)
+	}
+	return browser, closeBrowser, nil
+}
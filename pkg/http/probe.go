@@ -0,0 +1,57 @@
+package http
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+// applyProbe changes what the transport sends in the TLS handshake or the Host header
+// for the probe of the analyze, the regular request goes through unchanged
+func applyProbe(rt http.RoundTripper, transport *http.Transport, resource *utils.EndpointMetadata, analyze *utils.Analyze) http.RoundTripper {
+	switch analyze.Probe {
+	case utils.ProbeDecoySNI:
+		transport.TLSClientConfig.ServerName = *utils.DecoySNI
+	case utils.ProbeECH:
+		transport.TLSClientConfig.EncryptedClientHelloConfigList = analyze.ECHConfig
+		transport.TLSClientConfig.MinVersion = tls.VersionTLS13
+	case utils.ProbeHostMismatch, utils.ProbeNoSNI:
+		return &probeTransport{base: rt, probe: analyze.Probe, host: resource.Host, ip: analyze.IpDest}
+	}
+	return rt
+}
+
+// probeTransport rewrites the requests to the endpoint host
+type probeTransport struct {
+	base  http.RoundTripper
+	probe string
+	host  string
+	ip    string
+}
+
+func (p *probeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.EqualFold(req.URL.Hostname(), p.host) {
+		return p.base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	switch p.probe {
+	case utils.ProbeHostMismatch:
+		req.Host = *utils.DecoySNI
+	case utils.ProbeNoSNI:
+		// crypto/tls never sends an IP literal as SNI
+		req.Host = req.URL.Host
+		switch port := req.URL.Port(); {
+		case port != "":
+			req.URL.Host = net.JoinHostPort(p.ip, port)
+		case strings.Contains(p.ip, ":"):
+			req.URL.Host = "[" + p.ip + "]"
+		default:
+			req.URL.Host = p.ip
+		}
+	}
+	return p.base.RoundTrip(req)
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+	"golang.org/x/net/http2"
+)
+
+// forceProtocol restricts the transport to the protocol of the analyze, plain http
+// endpoints need the h2 transport to speak h2c with prior knowledge
+func forceProtocol(transport *http.Transport, resource *utils.EndpointMetadata, analyze *utils.Analyze) http.RoundTripper {
+	switch analyze.Protocol {
+	case utils.ProtocolH1:
+		transport.TLSClientConfig.NextProtos = []string{"http/1.1"}
+		transport.ForceAttemptHTTP2 = false
+		// a non nil empty map disables h2
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	case utils.ProtocolH2:
+		transport.TLSClientConfig.NextProtos = []string{"h2"}
+		transport.ForceAttemptHTTP2 = true
+		if resource.Scheme == "http" {
+			dial := transport.DialContext
+			return &schemeTransport{
+				plain: &http2.Transport{
+					AllowHTTP: true,
+					DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+						return dial(ctx, network, addr)
+					},
+				},
+				secure: transport,
+			}
+		}
+	}
+	return transport
+}
+
+// schemeTransport speaks h2c to http URLs and goes through TLS for https ones, a plain
+// http endpoint may redirect to https
+type schemeTransport struct {
+	plain  http.RoundTripper
+	secure http.RoundTripper
+}
+
+func (t *schemeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == "https" {
+		return t.secure.RoundTrip(req)
+	}
+	return t.plain.RoundTrip(req)
+}
+
+// parseAltSvc returns the alternatives of an Alt-Svc header, e.g. h3=":443", without their parameters
+func parseAltSvc(values []string) []string {
+	var alternatives []string
+	for _, value := range values {
+		for _, alternative := range strings.Split(value, ",") {
+			alternative = strings.TrimSpace(strings.SplitN(alternative, ";", 2)[0])
+			if alternative != "" && alternative != "clear" {
+				alternatives = append(alternatives, alternative)
+			}
+		}
+	}
+	return alternatives
+}
+
+// advertisesH3 tells if one of the alternatives is HTTP/3, drafts included
+func advertisesH3(alternatives []string) bool {
+	for _, alternative := range alternatives {
+		id := strings.SplitN(alternative, "=", 2)[0]
+		if id == "h3" || strings.HasPrefix(id, "h3-") {
+			return true
+		}
+	}
+	return false
+}
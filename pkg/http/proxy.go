@@ -0,0 +1,143 @@
+package http
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+// hop-by-hop headers are not forwarded by a proxy
+var hopHeaders = []string{
+	"Connection", "Proxy-Connection", "Keep-Alive", "Proxy-Authenticate",
+	"Proxy-Authorization", "Te", "Trailer", "Transfer-Encoding", "Upgrade",
+}
+
+// pinningProxy is a local HTTP proxy sending the endpoint host to the analyze destination,
+// the browser goes through it so that screenshots come from the server the hash was computed against
+type pinningProxy struct {
+	listener  net.Listener
+	server    *http.Server
+	dial      func(ctx context.Context, network, address string) (net.Conn, error)
+	transport *http.Transport
+}
+
+func startPinningProxy(resource *utils.EndpointMetadata, analyze *utils.Analyze) (*pinningProxy, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	dial := customDialer(resource.Host, analyze.IpDest, resource.Port)
+	proxy := &pinningProxy{
+		listener:  listener,
+		dial:      dial,
+		transport: &http.Transport{DialContext: dial, Proxy: nil},
+	}
+	proxy.server = &http.Server{Handler: proxy, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		if err := proxy.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("Error serving pinning proxy: %v\n", err)
+		}
+	}()
+	return proxy, nil
+}
+
+// URL is the proxy server given to the browser
+func (p *pinningProxy) URL() string {
+	return "http://" + p.listener.Addr().String()
+}
+
+// browserContext makes the incognito context of a capture go through the proxy
+func (p *pinningProxy) browserContext() chromedp.CreateBrowserContextOption {
+	return func(params *target.CreateBrowserContextParams) *target.CreateBrowserContextParams {
+		return params.WithProxyServer(p.URL())
+	}
+}
+
+func (p *pinningProxy) Close() {
+	p.server.Close()
+	p.transport.CloseIdleConnections()
+}
+
+func (p *pinningProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+		return
+	}
+	p.forward(w, r)
+}
+
+// tunnel serves CONNECT, used by the browser for https
+func (p *pinningProxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	upstream, err := p.dial(r.Context(), "tcp", r.Host)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		upstream.Close()
+		http.Error(w, "hijacking not supported", http.StatusInternalServerError)
+		return
+	}
+	client, buffered, err := hijacker.Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	if _, err := client.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		client.Close()
+		upstream.Close()
+		return
+	}
+
+	go func() {
+		// bytes the browser sent right after CONNECT may already be buffered
+		io.Copy(upstream, buffered)
+		upstream.Close()
+	}()
+	io.Copy(client, upstream)
+	client.Close()
+}
+
+// forward serves plain http requests, sent with an absolute URL
+func (p *pinningProxy) forward(w http.ResponseWriter, r *http.Request) {
+	if !r.URL.IsAbs() {
+		http.Error(w, "absolute URL expected", http.StatusBadRequest)
+		return
+	}
+
+	out := r.Clone(r.Context())
+	out.RequestURI = ""
+	for _, header := range hopHeaders {
+		out.Header.Del(header)
+	}
+
+	resp, err := p.transport.RoundTrip(out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for _, header := range hopHeaders {
+		resp.Header.Del(header)
+	}
+	for name, values := range resp.Header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
@@ -0,0 +1,205 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+const (
+	RedirectNone        = "none"
+	RedirectGeo         = "geo"
+	RedirectUnavailable = "unavailable"
+	RedirectScheme      = "https-upgrade"
+	RedirectCanonical   = "canonical"
+	RedirectLimit       = "limit-reached"
+	RedirectOther       = "other"
+)
+
+var unavailablePattern = regexp.MustCompile(`(?i)(not[-_]?available|unavailable|restricted|blocked|geo[-_]?block|access[-_]?denied|unsupported[-_]?(country|region)|country[-_]?not)`)
+
+// locale segment at the start of a path: /fr/, /en-us/, /pt_BR/
+var localePattern = regexp.MustCompile(`(?i)^/([a-z]{2})([-_][a-z]{2})?(/|$)`)
+
+var localeParams = []string{"country", "region", "locale", "lang", "hl", "gl"}
+
+// followRedirects performs the request itself for every hop so that each
+// status, Location and Set-Cookie is recorded, the last response is returned
+func followRedirects(ctx context.Context, client *http.Client, endpoint string, profile utils.Profile, limit uint) (*http.Response, []utils.RedirectHop, error) {
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	var hops []utils.RedirectHop
+	current := endpoint
+	method, body := profile.Method, profile.Body
+	for {
+		req, err := newProfileRequest(ctx, method, current, body, profile)
+		if err != nil {
+			return nil, hops, err
+		}
+
+		start := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, hops, err
+		}
+
+		hop := utils.RedirectHop{
+			URL:        current,
+			StatusCode: resp.StatusCode,
+			Location:   resp.Header.Get("Location"),
+			SetCookie:  resp.Header.Values("Set-Cookie"),
+			Duration:   time.Since(start),
+		}
+		hops = append(hops, hop)
+
+		if !isRedirect(resp.StatusCode) || hop.Location == "" || uint(len(hops)) > limit {
+			return resp, hops, nil
+		}
+
+		next, err := req.URL.Parse(hop.Location)
+		if err != nil {
+			resp.Body.Close()
+			return nil, hops, fmt.Errorf("invalid Location %q: %w", hop.Location, err)
+		}
+		resp.Body.Close()
+		current = next.String()
+
+		// like browsers, only 307 and 308 keep the method and the body
+		if resp.StatusCode != http.StatusTemporaryRedirect && resp.StatusCode != http.StatusPermanentRedirect {
+			if method != http.MethodHead {
+				method = http.MethodGet
+			}
+			body = ""
+		}
+	}
+}
+
+// newProfileRequest builds a request carrying the headers of the profile, cookies go through the jar
+func newProfileRequest(ctx context.Context, method, url, body string, profile utils.Profile) (*http.Request, error) {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range profile.Headers {
+		req.Header.Set(name, value)
+	}
+	if profile.UserAgent != "" {
+		req.Header.Set("User-Agent", profile.UserAgent)
+	}
+	if profile.AcceptLanguage != "" {
+		req.Header.Set("Accept-Language", profile.AcceptLanguage)
+	}
+
+	return req, nil
+}
+
+func isRedirect(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// ClassifyRedirects labels a redirect chain, geo and unavailable redirects win over cosmetic ones
+func ClassifyRedirects(hops []utils.RedirectHop, limit uint) string {
+	if uint(len(hops)) > limit && isRedirect(hops[len(hops)-1].StatusCode) {
+		return RedirectLimit
+	}
+	if len(hops) < 2 {
+		return RedirectNone
+	}
+
+	class := RedirectOther
+	for i := 1; i < len(hops); i++ {
+		from, err := url.Parse(hops[i-1].URL)
+		if err != nil {
+			continue
+		}
+		to, err := url.Parse(hops[i].URL)
+		if err != nil {
+			continue
+		}
+
+		if unavailablePattern.MatchString(to.Host + to.Path) {
+			return RedirectUnavailable
+		}
+		if isGeoRedirect(from, to) {
+			class = RedirectGeo
+			continue
+		}
+		if class == RedirectGeo {
+			continue
+		}
+
+		switch {
+		case from.Scheme == "http" && to.Scheme == "https" && from.Host == to.Host && from.Path == to.Path:
+			class = RedirectScheme
+		case isCanonicalRedirect(from, to):
+			if class != RedirectScheme {
+				class = RedirectCanonical
+			}
+		}
+	}
+
+	return class
+}
+
+// isGeoRedirect reports a redirect to another locale path, locale parameter, country subdomain or ccTLD,
+// two-letter segments only count when they are ISO 3166 or ISO 639 codes
+func isGeoRedirect(from, to *url.URL) bool {
+	toLocale := pathLocale(to.Path)
+	if toLocale != "" && !strings.EqualFold(pathLocale(from.Path), toLocale) {
+		return true
+	}
+
+	for _, param := range localeParams {
+		if to.Query().Get(param) != "" && from.Query().Get(param) != to.Query().Get(param) {
+			return true
+		}
+	}
+
+	// fr.example.com or example.fr
+	fromLabels := strings.Split(from.Hostname(), ".")
+	toLabels := strings.Split(to.Hostname(), ".")
+	if len(toLabels) > 2 && isLocaleCode(toLabels[0]) && !strings.EqualFold(toLabels[0], fromLabels[0]) {
+		return true
+	}
+	fromTLD, toTLD := fromLabels[len(fromLabels)-1], toLabels[len(toLabels)-1]
+	return !strings.EqualFold(fromTLD, toTLD) && isCountryTLD(toTLD)
+}
+
+// pathLocale returns the locale segment at the start of path, or an empty string when there
+// is none or its parts are not country or language codes
+func pathLocale(path string) string {
+	match := localePattern.FindStringSubmatch(path)
+	if match == nil || !isLocaleCode(match[1]) {
+		return ""
+	}
+	if match[2] != "" && !isLocaleCode(match[2][1:]) {
+		return ""
+	}
+	return match[1] + match[2]
+}
+
+func isCanonicalRedirect(from, to *url.URL) bool {
+	fromHost := strings.TrimPrefix(from.Hostname(), "www.")
+	toHost := strings.TrimPrefix(to.Hostname(), "www.")
+	return fromHost == toHost && strings.TrimSuffix(from.Path, "/") == strings.TrimSuffix(to.Path, "/")
+}
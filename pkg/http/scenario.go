@@ -0,0 +1,241 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/chromedp"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/emulation"
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+const (
+	ActionNavigate   = "navigate"
+	ActionClick      = "click"
+	ActionType       = "type"
+	ActionWait       = "wait"
+	ActionAssert     = "assert"
+	ActionScreenshot = "screenshot"
+)
+
+const defaultStepTimeout = 15 * time.Second
+
+// Step is one browser action, URL is resolved against the endpoint and Text is typed or asserted
+type Step struct {
+	Action   string `json:"action"`
+	URL      string `json:"url"`
+	Selector string `json:"selector"`
+	Text     string `json:"text"`
+	Name     string `json:"name"`
+	// Timeout of the step, e.g. "30s"
+	Timeout string `json:"timeout"`
+
+	timeout time.Duration
+}
+
+// Scenario is run as is from every country, it stops at the first failing step
+type Scenario struct {
+	Name  string `json:"name"`
+	Steps []Step `json:"steps"`
+}
+
+// LoadScenario reads a JSON scenario, nothing is run when path is empty
+func LoadScenario(path string) (*Scenario, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var scenario Scenario
+	if err := json.Unmarshal(content, &scenario); err != nil {
+		return nil, err
+	}
+
+	for i := range scenario.Steps {
+		if err := scenario.Steps[i].validate(); err != nil {
+			return nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+	return &scenario, nil
+}
+
+func (s *Step) validate() error {
+	s.timeout = defaultStepTimeout
+	if s.Timeout != "" {
+		timeout, err := time.ParseDuration(s.Timeout)
+		if err != nil {
+			return err
+		}
+		s.timeout = timeout
+	}
+
+	switch s.Action {
+	case ActionNavigate, ActionScreenshot:
+	case ActionClick, ActionWait:
+		if s.Selector == "" {
+			return fmt.Errorf("%s needs a selector", s.Action)
+		}
+	case ActionType, ActionAssert:
+		if s.Selector == "" || s.Text == "" {
+			return fmt.Errorf("%s needs a selector and a text", s.Action)
+		}
+	default:
+		return fmt.Errorf("unknown action %q", s.Action)
+	}
+	return nil
+}
+
+// RunScenarioByCountryCode runs the scenario once per destination IP concurrently, the pool caps how many run at once.
+// The browser ignores the profile and the protocol, the other analyzes of the destination get the same steps
+func RunScenarioByCountryCode(ctx context.Context, pool *BrowserPool, res *utils.GeoIP, analyzes []*utils.Analyze, scenario *Scenario) {
+	var wg sync.WaitGroup
+	for _, group := range byDestination(analyzes) {
+		wg.Add(1)
+		go func(group []*utils.Analyze) {
+			defer wg.Done()
+			if err := runScenario(ctx, pool, &res.Resource, res.Emulation, group[0], scenario); err != nil {
+				log.Printf("Error running scenario %s: %v\n", scenario.Name, err)
+			}
+			for _, analyze := range group[1:] {
+				analyze.Steps = group[0].Steps
+				analyze.Emulation = group[0].Emulation
+			}
+		}(group)
+	}
+	wg.Wait()
+}
+
+// runScenario runs every step in one tab, the screenshot and the DOM after each step are saved
+func runScenario(ctx context.Context, pool *BrowserPool, resource *utils.EndpointMetadata, mode emulation.Mode, analyze *utils.Analyze, scenario *Scenario) error {
+	proxy, err := startPinningProxy(resource, analyze)
+	if err != nil {
+		return fmt.Errorf("failed to start pinning proxy: %w", err)
+	}
+	defer proxy.Close()
+
+	ctx, release, err := pool.Acquire(ctx, proxy.browserContext())
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// the first Run attaches the tab and its event loop lives as long as the ctx of that Run,
+	// so it gets the tab itself, every step has its own deadline and the emulation the request one
+	if err := chromedp.Run(ctx); err != nil {
+		return fmt.Errorf("failed to open the tab: %w", err)
+	}
+	emulateCtx, cancelEmulate := context.WithTimeout(ctx, *utils.RequestTimeout)
+	err = emulate(emulateCtx, mode, analyze)
+	cancelEmulate()
+	if err != nil {
+		return fmt.Errorf("failed to emulate the country: %w", err)
+	}
+
+	if err := os.MkdirAll(*utils.FolderPath, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create folder: %w", err)
+	}
+
+	analyze.Steps = nil
+	for i, step := range scenario.Steps {
+		result := utils.StepResult{Index: i + 1, Action: step.Action, Name: step.Name}
+
+		stepCtx, cancelStep := context.WithTimeout(ctx, step.timeout)
+		err := chromedp.Run(stepCtx, step.tasks(resource))
+		if err == nil {
+			err = saveStep(stepCtx, resource, analyze, scenario, step, &result)
+		}
+		cancelStep()
+
+		if err != nil {
+			result.Error = err.Error()
+			analyze.Steps = append(analyze.Steps, result)
+			return fmt.Errorf("step %d (%s): %w", i+1, step.Action, err)
+		}
+		analyze.Steps = append(analyze.Steps, result)
+	}
+	return nil
+}
+
+func (s Step) tasks(resource *utils.EndpointMetadata) chromedp.Tasks {
+	switch s.Action {
+	case ActionNavigate:
+		target := resource.Endpoint
+		if s.URL != "" {
+			target = resolveURL(resource.Endpoint, s.URL)
+		}
+		return chromedp.Tasks{chromedp.Navigate(target), chromedp.WaitReady("body", chromedp.ByQuery)}
+	case ActionClick:
+		return chromedp.Tasks{chromedp.Click(s.Selector, chromedp.ByQuery, chromedp.NodeVisible)}
+	case ActionType:
+		return chromedp.Tasks{chromedp.SendKeys(s.Selector, s.Text, chromedp.ByQuery, chromedp.NodeVisible)}
+	case ActionWait:
+		return chromedp.Tasks{chromedp.WaitVisible(s.Selector, chromedp.ByQuery)}
+	case ActionAssert:
+		return chromedp.Tasks{chromedp.ActionFunc(func(ctx context.Context) error {
+			var text string
+			if err := chromedp.Text(s.Selector, &text, chromedp.ByQuery, chromedp.NodeVisible).Do(ctx); err != nil {
+				return err
+			}
+			if !strings.Contains(text, s.Text) {
+				return fmt.Errorf("%q not found in %s", s.Text, s.Selector)
+			}
+			return nil
+		})}
+	}
+	return nil
+}
+
+// saveStep stores the screenshot, full page for a screenshot step, and the DOM reached by the step
+func saveStep(ctx context.Context, resource *utils.EndpointMetadata, analyze *utils.Analyze, scenario *Scenario, step Step, result *utils.StepResult) error {
+	var screenshot []byte
+	var dom string
+	capture := chromedp.CaptureScreenshot(&screenshot)
+	if step.Action == ActionScreenshot {
+		capture = chromedp.FullScreenshot(&screenshot, 100)
+	}
+	if err := chromedp.Run(ctx, capture, chromedp.Evaluate("document.documentElement.outerHTML", &dom)); err != nil {
+		return err
+	}
+
+	label := strconv.Itoa(result.Index)
+	if step.Name != "" {
+		label += "-" + step.Name
+	}
+	result.Screenshot = resource.ArtifactName("png", analyze.CountryCode, analyze.IpDest, scenario.Name, label)
+	result.DOM = resource.ArtifactName("html", analyze.CountryCode, analyze.IpDest, scenario.Name, label)
+	result.DOMHash = utils.HashByte([]byte(dom))
+
+	if err := os.WriteFile(filepath.Join(*utils.FolderPath, result.Screenshot), screenshot, 0644); err != nil {
+		return fmt.Errorf("failed to save screenshot: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(*utils.FolderPath, result.DOM), []byte(dom), 0644); err != nil {
+		return fmt.Errorf("failed to save DOM: %w", err)
+	}
+	return nil
+}
+
+func resolveURL(base, ref string) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	target, err := u.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return target.String()
+}
@@ -0,0 +1,183 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/emulation"
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+type cdpMessage struct {
+	ID        int64           `json:"id,omitempty"`
+	Method    string          `json:"method,omitempty"`
+	Params    json.RawMessage `json:"params,omitempty"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	SessionID string          `json:"sessionId,omitempty"`
+}
+
+// fakeBrowser answers the DevTools protocol over a websocket like a browser with one blank page,
+// every command it does not know succeeds with an empty result
+func fakeBrowser(t *testing.T) string {
+	t.Helper()
+	var targets atomic.Int64
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, _, err := ws.UpgradeHTTP(r, w)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		send := func(msg cdpMessage) error {
+			data, _ := json.Marshal(msg)
+			return wsutil.WriteServerText(conn, data)
+		}
+		for {
+			data, err := wsutil.ReadClientText(conn)
+			if err != nil {
+				return
+			}
+			var msg cdpMessage
+			if err := json.Unmarshal(data, &msg); err != nil {
+				return
+			}
+
+			result := "{}"
+			switch msg.Method {
+			case "Target.createBrowserContext":
+				result = `{"browserContextId":"context"}`
+			case "Target.createTarget":
+				result = fmt.Sprintf(`{"targetId":"page%d"}`, targets.Add(1))
+			case "Target.attachToTarget":
+				var params struct {
+					TargetID string `json:"targetId"`
+				}
+				json.Unmarshal(msg.Params, &params)
+				result = fmt.Sprintf(`{"sessionId":"session-%s"}`, params.TargetID)
+			case "Runtime.evaluate":
+				result = `{"result":{"type":"string","value":"<html><body>step</body></html>"}}`
+				if strings.Contains(string(msg.Params), `"self"`) {
+					result = `{"result":{"type":"object","className":"Window"}}`
+				}
+			case "Page.captureScreenshot":
+				result = `{"data":"cG5n"}`
+			}
+			if err := send(cdpMessage{ID: msg.ID, Result: json.RawMessage(result), SessionID: msg.SessionID}); err != nil {
+				return
+			}
+
+			// the first tab of the browser
+			if msg.Method == "Target.setDiscoverTargets" && msg.SessionID == "" {
+				send(cdpMessage{
+					Method: "Target.targetCreated",
+					Params: json.RawMessage(`{"targetInfo":{"targetId":"page0","type":"page","title":"","url":"about:blank","attached":false,"canAccessOpener":false}}`),
+				})
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws://" + server.Listener.Addr().String() + "/devtools/browser/fake"
+}
+
+func TestRunScenario(t *testing.T) {
+	folder := t.TempDir()
+	timeout := 5 * time.Second
+	utils.FolderPath, utils.RequestTimeout = &folder, &timeout
+
+	wsURL := fakeBrowser(t)
+	pool := NewBrowserPool(1)
+	pool.start = func() (context.Context, context.CancelFunc, error) {
+		allocCtx, cancelAlloc := chromedp.NewRemoteAllocator(context.Background(), wsURL)
+		browser, cancelBrowser := chromedp.NewContext(allocCtx)
+		if err := chromedp.Run(browser); err != nil {
+			cancelBrowser()
+			cancelAlloc()
+			return nil, nil, err
+		}
+		return browser, func() { cancelBrowser(); cancelAlloc() }, nil
+	}
+	defer pool.Close()
+
+	// a step that outlives the event loop of the tab waits for its whole timeout
+	scenario := &Scenario{Name: "steps", Steps: []Step{
+		{Action: ActionScreenshot, Name: "first", timeout: 2 * time.Second},
+		{Action: ActionScreenshot, Name: "second", timeout: 2 * time.Second},
+		{Action: ActionScreenshot, Name: "third", timeout: 2 * time.Second},
+	}}
+	resource := &utils.EndpointMetadata{Endpoint: "https://example.com/", Scheme: "https", Host: "example.com", Port: "443"}
+
+	tests := []struct {
+		name     string
+		mode     emulation.Mode
+		timezone string
+	}{
+		{name: "Without emulation", mode: emulation.Mode{Disabled: true}},
+		{name: "Emulated country", mode: emulation.Mode{}, timezone: "Europe/Paris"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyze := &utils.Analyze{CountryCode: "fr", IpDest: "192.0.2.1"}
+
+			start := time.Now()
+			if err := runScenario(context.Background(), pool, resource, tt.mode, analyze, scenario); err != nil {
+				t.Fatalf("runScenario() error = %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("runScenario() took %v, a step waited for its timeout", elapsed)
+			}
+
+			if tt.timezone != "" && (analyze.Emulation == nil || analyze.Emulation.Timezone != tt.timezone) {
+				t.Errorf("runScenario() emulation = %+v, expected timezone %s", analyze.Emulation, tt.timezone)
+			}
+			if len(analyze.Steps) != len(scenario.Steps) {
+				t.Fatalf("runScenario() ran %d steps, expected %d: %+v", len(analyze.Steps), len(scenario.Steps), analyze.Steps)
+			}
+			for _, step := range analyze.Steps {
+				if step.Error != "" {
+					t.Errorf("step %d failed: %s", step.Index, step.Error)
+				}
+				dom, err := os.ReadFile(filepath.Join(folder, step.DOM))
+				if err != nil || string(dom) != "<html><body>step</body></html>" {
+					t.Errorf("step %d DOM = %q, %v", step.Index, dom, err)
+				}
+			}
+		})
+	}
+}
+
+func TestByDestination(t *testing.T) {
+	analyzes := []*utils.Analyze{
+		{CountryCode: "FR", IpDest: "192.0.2.1", Profile: utils.Profile{Name: "desktop"}},
+		{CountryCode: "FR", IpDest: "192.0.2.2", Profile: utils.Profile{Name: "desktop"}},
+		{CountryCode: "FR", IpDest: "192.0.2.1", Profile: utils.Profile{Name: "mobile"}},
+		{CountryCode: "DE", IpDest: "192.0.2.1", Profile: utils.Profile{Name: "desktop"}},
+		{CountryCode: "FR", IpDest: "192.0.2.1", Protocol: utils.ProtocolH2},
+	}
+
+	groups := byDestination(analyzes)
+	expected := [][]*utils.Analyze{
+		{analyzes[0], analyzes[2], analyzes[4]},
+		{analyzes[1]},
+		{analyzes[3]},
+	}
+	if !reflect.DeepEqual(groups, expected) {
+		t.Errorf("byDestination() got %d groups %v, expected %v", len(groups), groups, expected)
+	}
+}
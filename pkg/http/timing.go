@@ -0,0 +1,85 @@
+package http
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+// timingTransport traces every round trip, only the last one is kept,
+// which is the final response once the redirects are followed
+type timingTransport struct {
+	base http.RoundTripper
+
+	mu                                                  sync.Mutex
+	start, connectStart, connectDone, tlsStart, tlsDone time.Time
+	firstByte                                           time.Time
+	reused                                              bool
+}
+
+func (t *timingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.start, t.firstByte = time.Now(), time.Time{}
+	t.connectStart, t.connectDone = time.Time{}, time.Time{}
+	t.tlsStart, t.tlsDone = time.Time{}, time.Time{}
+	t.reused = false
+	t.mu.Unlock()
+
+	return t.base.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), t.clientTrace())))
+}
+
+func (t *timingTransport) clientTrace() *httptrace.ClientTrace {
+	mark := func(field *time.Time) {
+		t.mu.Lock()
+		*field = time.Now()
+		t.mu.Unlock()
+	}
+
+	return &httptrace.ClientTrace{
+		ConnectStart:      func(string, string) { mark(&t.connectStart) },
+		ConnectDone:       func(string, string, error) { mark(&t.connectDone) },
+		TLSHandshakeStart: func() { mark(&t.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { mark(&t.tlsDone) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.reused = info.Reused
+			t.mu.Unlock()
+		},
+		GotFirstResponseByte: func() { mark(&t.firstByte) },
+	}
+}
+
+// timing measures the last round trip up to end, the moment its body was read. The endpoint
+// host is dialed on the pinned IP, DNS is the resolution time measured by the DNS stage
+func (t *timingTransport) timing(end time.Time, resolution time.Duration) *utils.Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.start.IsZero() {
+		return nil
+	}
+
+	timing := &utils.Timing{
+		DNS:     resolution,
+		Connect: between(t.connectStart, t.connectDone),
+		TLS:     between(t.tlsStart, t.tlsDone),
+		TTFB:    between(t.start, t.firstByte),
+		Total:   end.Sub(t.start),
+		Reused:  t.reused,
+	}
+	if !t.firstByte.IsZero() {
+		timing.Download = end.Sub(t.firstByte)
+	}
+	return timing
+}
+
+func between(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return end.Sub(start)
+}
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+	"golang.org/x/crypto/ocsp"
+)
+
+// certVerifier lets every handshake succeed and keeps the verification
+// error per server name, an invalid certificate is a result, not a failure
+type certVerifier struct {
+	mu     sync.Mutex
+	errors map[string]string
+}
+
+func newCertVerifier() *certVerifier {
+	return &certVerifier{errors: make(map[string]string)}
+}
+
+func (v *certVerifier) verifyConnection(cs tls.ConnectionState) error {
+	var message string
+	if err := verifyChain(cs, nil); err != nil {
+		message = err.Error()
+	}
+
+	v.mu.Lock()
+	v.errors[cs.ServerName] = message
+	v.mu.Unlock()
+
+	return nil
+}
+
+func (v *certVerifier) errorFor(serverName string) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.errors[serverName]
+}
+
+// verifyChain verifies the presented chain for the server name, roots is nil for the system roots
+func verifyChain(cs tls.ConnectionState, roots *x509.CertPool) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("no certificate presented")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       cs.ServerName,
+		Intermediates: intermediates,
+		Roots:         roots,
+	})
+	return err
+}
+
+// inspectTLS records the negotiated parameters and the certificate chain of a connection
+func inspectTLS(cs *tls.ConnectionState, verifyError string) *utils.TLSInfo {
+	if cs == nil {
+		return nil
+	}
+
+	info := &utils.TLSInfo{
+		Version:     tls.VersionName(cs.Version),
+		CipherSuite: tls.CipherSuiteName(cs.CipherSuite),
+		ALPN:        cs.NegotiatedProtocol,
+		ServerName:  cs.ServerName,
+		OCSPStatus:  ocspStatus(cs),
+		VerifyError: verifyError,
+	}
+
+	for _, cert := range cs.PeerCertificates {
+		spki := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+		info.Chain = append(info.Chain, utils.Certificate{
+			Subject:   cert.Subject.String(),
+			SANs:      cert.DNSNames,
+			Issuer:    cert.Issuer.String(),
+			Serial:    cert.SerialNumber.Text(16),
+			NotBefore: cert.NotBefore,
+			NotAfter:  cert.NotAfter,
+			SPKIHash:  hex.EncodeToString(spki[:]),
+		})
+	}
+
+	return info
+}
+
+func ocspStatus(cs *tls.ConnectionState) string {
+	if len(cs.OCSPResponse) == 0 {
+		return utils.OCSPNone
+	}
+
+	var issuer *x509.Certificate
+	if len(cs.PeerCertificates) > 1 {
+		issuer = cs.PeerCertificates[1]
+	}
+	resp, err := ocsp.ParseResponse(cs.OCSPResponse, issuer)
+	if err != nil {
+		return utils.OCSPInvalid
+	}
+
+	switch {
+	case resp.Status == ocsp.Revoked:
+		return utils.OCSPRevoked
+	case resp.Status == ocsp.Unknown:
+		return utils.OCSPUnknown
+	case !resp.NextUpdate.IsZero() && resp.NextUpdate.Before(time.Now()):
+		return utils.OCSPExpired
+	default:
+		return utils.OCSPGood
+	}
+}
@@ -0,0 +1,192 @@
+package http
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+	"golang.org/x/crypto/ocsp"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+func newTestCert(t *testing.T, template *x509.Certificate, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, issuer := crypto.Signer(key), template
+	if parent != nil {
+		signer, issuer = parent.key, parent.cert
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, key.Public(), signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key}
+}
+
+func newTestCA(t *testing.T) *testCert {
+	return newTestCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, nil)
+}
+
+func newTestLeaf(t *testing.T, ca *testCert, notAfter time.Time) *testCert {
+	return newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(0x2a),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    time.Now().Add(-48 * time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca)
+}
+
+func TestVerifyChain(t *testing.T) {
+	ca := newTestCA(t)
+	leaf := newTestLeaf(t, ca, time.Now().Add(time.Hour))
+	expired := newTestLeaf(t, ca, time.Now().Add(-time.Hour))
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	tests := []struct {
+		name    string
+		cs      tls.ConnectionState
+		wantErr bool
+	}{
+		{
+			name: "Valid",
+			cs:   tls.ConnectionState{ServerName: "example.com", PeerCertificates: []*x509.Certificate{leaf.cert}},
+		},
+		{
+			name:    "Wrong name",
+			cs:      tls.ConnectionState{ServerName: "example.org", PeerCertificates: []*x509.Certificate{leaf.cert}},
+			wantErr: true,
+		},
+		{
+			name:    "Expired",
+			cs:      tls.ConnectionState{ServerName: "example.com", PeerCertificates: []*x509.Certificate{expired.cert}},
+			wantErr: true,
+		},
+		{
+			name:    "No certificate",
+			cs:      tls.ConnectionState{ServerName: "example.com"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyChain(tt.cs, roots)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("verifyChain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInspectTLS(t *testing.T) {
+	if inspectTLS(nil, "") != nil {
+		t.Errorf("inspectTLS(nil) expected nil")
+	}
+
+	ca := newTestCA(t)
+	leaf := newTestLeaf(t, ca, time.Now().Add(time.Hour))
+	cs := &tls.ConnectionState{
+		Version:            tls.VersionTLS13,
+		CipherSuite:        tls.TLS_AES_128_GCM_SHA256,
+		NegotiatedProtocol: "h2",
+		ServerName:         "example.com",
+		PeerCertificates:   []*x509.Certificate{leaf.cert, ca.cert},
+	}
+
+	info := inspectTLS(cs, "x509: certificate signed by unknown authority")
+	if info.Version != "TLS 1.3" || info.CipherSuite != "TLS_AES_128_GCM_SHA256" || info.ALPN != "h2" ||
+		info.ServerName != "example.com" || info.OCSPStatus != utils.OCSPNone ||
+		info.VerifyError != "x509: certificate signed by unknown authority" {
+		t.Errorf("inspectTLS() got = %+v", info)
+	}
+	if len(info.Chain) != 2 {
+		t.Fatalf("inspectTLS() chain length = %d, expected 2", len(info.Chain))
+	}
+
+	got := info.Chain[0]
+	spki := sha256.Sum256(leaf.cert.RawSubjectPublicKeyInfo)
+	if got.Subject != "CN=example.com" || got.Issuer != "CN=Test CA" || got.Serial != "2a" ||
+		len(got.SANs) != 1 || got.SANs[0] != "example.com" || got.SPKIHash != hex.EncodeToString(spki[:]) ||
+		!got.NotAfter.Equal(leaf.cert.NotAfter) {
+		t.Errorf("inspectTLS() leaf = %+v", got)
+	}
+}
+
+func TestOCSPStatus(t *testing.T) {
+	ca := newTestCA(t)
+	leaf := newTestLeaf(t, ca, time.Now().Add(time.Hour))
+
+	staple := func(status int, nextUpdate time.Time) []byte {
+		der, err := ocsp.CreateResponse(ca.cert, ca.cert, ocsp.Response{
+			Status:       status,
+			SerialNumber: leaf.cert.SerialNumber,
+			ThisUpdate:   time.Now().Add(-2 * time.Hour),
+			NextUpdate:   nextUpdate,
+			RevokedAt:    time.Now().Add(-time.Hour),
+		}, ca.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return der
+	}
+	later, earlier := time.Now().Add(time.Hour), time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		response []byte
+		expected string
+	}{
+		{name: "No staple", response: nil, expected: utils.OCSPNone},
+		{name: "Good", response: staple(ocsp.Good, later), expected: utils.OCSPGood},
+		{name: "Revoked", response: staple(ocsp.Revoked, later), expected: utils.OCSPRevoked},
+		{name: "Unknown", response: staple(ocsp.Unknown, later), expected: utils.OCSPUnknown},
+		{name: "Expired", response: staple(ocsp.Good, earlier), expected: utils.OCSPExpired},
+		{name: "Invalid", response: []byte("not an ocsp response"), expected: utils.OCSPInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{leaf.cert, ca.cert},
+				OCSPResponse:     tt.response,
+			}
+			if got := ocspStatus(cs); got != tt.expected {
+				t.Errorf("ocspStatus() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
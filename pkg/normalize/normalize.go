@@ -0,0 +1,139 @@
+package normalize
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+)
+
+type Replacement struct {
+	Pattern string `json:"pattern"`
+	With    string `json:"with"`
+
+	re *regexp.Regexp
+}
+
+// Rules is the normalization pipeline of the endpoints matching Match,
+// a host, a URL prefix or * for every endpoint
+type Rules struct {
+	Match        string        `json:"match"`
+	Remove       []string      `json:"remove"`
+	StripScripts bool          `json:"strip_scripts"`
+	StripStyles  bool          `json:"strip_styles"`
+	Replace      []Replacement `json:"replace"`
+	Whitespace   bool          `json:"whitespace"`
+
+	selectors []cascadia.Sel
+}
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+// Load reads a JSON list of rules and compiles their patterns and selectors
+func Load(path string) ([]Rules, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []Rules
+	if err := json.Unmarshal(content, &rules); err != nil {
+		return nil, err
+	}
+
+	for i := range rules {
+		if err := rules[i].compile(); err != nil {
+			return nil, fmt.Errorf("rules for %q: %w", rules[i].Match, err)
+		}
+	}
+
+	return rules, nil
+}
+
+func (r *Rules) compile() error {
+	for i := range r.Replace {
+		re, err := regexp.Compile(r.Replace[i].Pattern)
+		if err != nil {
+			return err
+		}
+		r.Replace[i].re = re
+	}
+
+	if r.StripScripts {
+		r.Remove = append(r.Remove, "script", "noscript")
+	}
+	if r.StripStyles {
+		r.Remove = append(r.Remove, "style", "link[rel=stylesheet]")
+	}
+	r.selectors = nil
+	for _, selector := range r.Remove {
+		sel, err := cascadia.Parse(selector)
+		if err != nil {
+			return fmt.Errorf("selector %q: %w", selector, err)
+		}
+		r.selectors = append(r.selectors, sel)
+	}
+
+	return nil
+}
+
+// For returns the first rules matching the endpoint, nil when the body is hashed as is
+func For(rules []Rules, endpoint, host string) *Rules {
+	for i := range rules {
+		match := rules[i].Match
+		if match == "*" || match == host || (match != "" && strings.HasPrefix(endpoint, match)) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// Apply removes the selected elements, then runs the replacements and
+// collapses whitespace on the rendered document
+func Apply(rules *Rules, body []byte) ([]byte, error) {
+	if rules == nil {
+		return body, nil
+	}
+
+	if len(rules.selectors) > 0 {
+		doc, err := html.Parse(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+
+		var matched []*html.Node
+		for _, sel := range rules.selectors {
+			matched = append(matched, cascadia.QueryAll(doc, sel)...)
+		}
+		for _, node := range matched {
+			if node.Parent != nil {
+				node.Parent.RemoveChild(node)
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := html.Render(&buf, doc); err != nil {
+			return nil, err
+		}
+		body = buf.Bytes()
+	}
+
+	for _, replacement := range rules.Replace {
+		body = replacement.re.ReplaceAll(body, []byte(replacement.With))
+	}
+
+	if rules.Whitespace {
+		body = bytes.TrimSpace(whitespace.ReplaceAll(body, []byte(" ")))
+	}
+
+	return body, nil
+}
@@ -0,0 +1,57 @@
+package normalize
+
+import (
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    *Rules
+		input    string
+		expected string
+	}{
+		{
+			name:     "No rules",
+			rules:    nil,
+			input:    "<p>raw  body</p>",
+			expected: "<p>raw  body</p>",
+		},
+		{
+			name:     "Strip scripts and collapse whitespace",
+			rules:    &Rules{StripScripts: true, Whitespace: true},
+			input:    "<html><head><script>var t = 1;</script></head><body>\n  <p>hello</p>\n</body></html>",
+			expected: "<html><head></head><body> <p>hello</p> </body></html>",
+		},
+		{
+			name:     "Remove by selector",
+			rules:    &Rules{Remove: []string{"input[name=csrf]", ".ab-test"}},
+			input:    `<html><head></head><body><form><input name="csrf" value="x1"/></form><div class="ab-test">B</div></body></html>`,
+			expected: `<html><head></head><body><form></form></body></html>`,
+		},
+		{
+			name:     "Replace nonce",
+			rules:    &Rules{Replace: []Replacement{{Pattern: `nonce-[0-9a-f]+`, With: "nonce"}}},
+			input:    "token nonce-1a2b3c",
+			expected: "token nonce",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.rules != nil {
+				if err := tt.rules.compile(); err != nil {
+					t.Fatalf("compile() error = %v", err)
+				}
+			}
+			got, err := Apply(tt.rules, []byte(tt.input))
+			if err != nil {
+				t.Errorf("Apply() error = %v", err)
+				return
+			}
+			if string(got) != tt.expected {
+				t.Errorf("Apply() got = %v, expected = %v", string(got), tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package page
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Parse returns the document tree of an HTML body
+func Parse(body []byte) (*html.Node, error) {
+	return html.Parse(bytes.NewReader(body))
+}
+
+// hidden elements never render text
+func hidden(n *html.Node) bool {
+	switch n.DataAtom {
+	case atom.Head, atom.Script, atom.Style, atom.Noscript, atom.Template, atom.Iframe, atom.Svg:
+		return true
+	}
+	return false
+}
+
+// VisibleText returns the text a visitor can read, one line per block of text
+func VisibleText(doc *html.Node) string {
+	var lines []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && hidden(n) {
+			return
+		}
+		if n.Type == html.TextNode {
+			if text := strings.Join(strings.Fields(n.Data), " "); text != "" {
+				lines = append(lines, text)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	return strings.Join(lines, "\n")
+}
+
+// TagPaths returns the path from the root to every element, e.g. html>body>div>p
+func TagPaths(doc *html.Node) []string {
+	var paths []string
+	var walk func(n *html.Node, parent string)
+	walk = func(n *html.Node, parent string) {
+		path := parent
+		if n.Type == html.ElementNode {
+			if path != "" {
+				path += ">"
+			}
+			path += n.Data
+			paths = append(paths, path)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, path)
+		}
+	}
+	walk(doc, "")
+
+	return paths
+}
+
+// Content is what the diff stage compares: the visible text and the attributes that usually change between regions
+type Content struct {
+	Title     string
+	Lang      string
+	Canonical string
+	Meta      map[string]string
+	Hreflang  []string
+	Text      string
+}
+
+// Extract reads the title, lang, canonical link, named meta tags and hreflang alternates of a document
+func Extract(doc *html.Node) Content {
+	content := Content{Meta: make(map[string]string)}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Html:
+				content.Lang = attr(n, "lang")
+			case atom.Title:
+				if content.Title == "" && n.FirstChild != nil {
+					content.Title = strings.Join(strings.Fields(n.FirstChild.Data), " ")
+				}
+			case atom.Meta:
+				name := attr(n, "name")
+				if name == "" {
+					name = attr(n, "property")
+				}
+				if name != "" {
+					content.Meta[strings.ToLower(name)] = attr(n, "content")
+				}
+			case atom.Link:
+				rel := strings.ToLower(attr(n, "rel"))
+				switch {
+				case rel == "canonical":
+					content.Canonical = attr(n, "href")
+				case rel == "alternate" && attr(n, "hreflang") != "":
+					content.Hreflang = append(content.Hreflang, attr(n, "hreflang")+" "+attr(n, "href"))
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	sort.Strings(content.Hreflang)
+	content.Text = VisibleText(doc)
+	return content
+}
+
+// Lines renders the content one attribute per line followed by the visible text, ready to be diffed
+func (c Content) Lines() []string {
+	lines := []string{
+		"title: " + c.Title,
+		"lang: " + c.Lang,
+		"canonical: " + c.Canonical,
+	}
+
+	names := make([]string, 0, len(c.Meta))
+	for name := range c.Meta {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		lines = append(lines, "meta "+name+": "+c.Meta[name])
+	}
+	for _, alternate := range c.Hreflang {
+		lines = append(lines, "hreflang: "+alternate)
+	}
+
+	lines = append(lines, "")
+	if c.Text != "" {
+		lines = append(lines, strings.Split(c.Text, "\n")...)
+	}
+	return lines
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// Links returns the href of every anchor of a document, as written
+func Links(doc *html.Node) []string {
+	var links []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && (n.DataAtom == atom.A || n.DataAtom == atom.Area) {
+			if href := strings.TrimSpace(attr(n, "href")); href != "" {
+				links = append(links, href)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	return links
+}
+
+// Resource is a subresource referenced by a document, Kind is the element loading it
+type Resource struct {
+	Kind string
+	URL  string
+}
+
+// Subresources returns the scripts, stylesheets, icons, preloads, images, frames and media of a document, as written
+func Subresources(doc *html.Node) []Resource {
+	var resources []Resource
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			var ref string
+			switch n.DataAtom {
+			case atom.Script, atom.Img, atom.Iframe, atom.Video, atom.Audio, atom.Source, atom.Embed:
+				ref = attr(n, "src")
+			case atom.Link:
+				switch strings.ToLower(attr(n, "rel")) {
+				case "stylesheet", "icon", "shortcut icon", "preload", "modulepreload", "manifest":
+					ref = attr(n, "href")
+				}
+			}
+			if ref = strings.TrimSpace(ref); ref != "" {
+				resources = append(resources, Resource{Kind: n.Data, URL: ref})
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	return resources
+}
@@ -0,0 +1,48 @@
+package page
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSubresources(t *testing.T) {
+	body := `<html><head><link rel="stylesheet" href="/main.css"><link rel="canonical" href="/"><script src="https://cdn.example.net/app.js"></script><script>inline()</script></head>` +
+		`<body><img src="logo.png"><iframe src="/ad"></iframe><a href="/next">next</a></body></html>`
+	expected := []Resource{
+		{Kind: "link", URL: "/main.css"},
+		{Kind: "script", URL: "https://cdn.example.net/app.js"},
+		{Kind: "img", URL: "logo.png"},
+		{Kind: "iframe", URL: "/ad"},
+	}
+
+	doc, err := Parse([]byte(body))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := Subresources(doc); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Subresources() got = %v, expected = %v", got, expected)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	body := `<html lang="fr"><head><title> Boutique </title><meta name="description" content="Livraison"><link rel="canonical" href="https://example.com/fr/">` +
+		`<link rel="alternate" hreflang="en" href="https://example.com/en/"></head><body><h1>Bienvenue</h1><script>var x;</script><p>Prix</p></body></html>`
+	expected := []string{
+		"title: Boutique",
+		"lang: fr",
+		"canonical: https://example.com/fr/",
+		"meta description: Livraison",
+		"hreflang: en https://example.com/en/",
+		"",
+		"Bienvenue",
+		"Prix",
+	}
+
+	doc, err := Parse([]byte(body))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := Extract(doc).Lines(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Extract() got = %q, expected = %q", got, expected)
+	}
+}
@@ -0,0 +1,71 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+// CompareProbes compares the probes of each destination with its regular request of the same profile and protocol.
+// A regular request failing at the network or TLS level while a probe hiding the SNI gets an answer points to
+// SNI filtering on the path, an answer blocked whatever the SNI points to the origin
+func CompareProbes(data []utils.Analyze) []string {
+	regular := make(map[string]utils.Analyze)
+	for _, entry := range data {
+		if entry.Probe == "" {
+			regular[probeKey(entry)] = entry
+		}
+	}
+
+	var notes []string
+	for _, entry := range data {
+		if entry.Probe == "" {
+			continue
+		}
+		base, ok := regular[probeKey(entry)]
+		if !ok {
+			continue
+		}
+		where := fmt.Sprintf("%s (%s)", entry.CountryCode, entry.IpDest)
+
+		switch {
+		case failed(base) && !failed(entry) && hidesSNI(entry.Probe):
+			notes = append(notes, fmt.Sprintf("%s: the regular request failed (%s) but %s got %d, SNI filtering suspected",
+				where, base.Error, entry.Probe, entry.StatusCode))
+		case !failed(base) && failed(entry) && !hidesSNI(entry.Probe):
+			notes = append(notes, fmt.Sprintf("%s: %s failed (%s) while the regular request got %d",
+				where, entry.Probe, entry.Error, base.StatusCode))
+		case !failed(base) && !failed(entry) && base.Status != utils.StatusOnline && entry.Status == base.Status && hidesSNI(entry.Probe):
+			notes = append(notes, fmt.Sprintf("%s: %s is answered %d as well, the block does not depend on the SNI",
+				where, entry.Probe, entry.StatusCode))
+		}
+	}
+
+	return notes
+}
+
+func probeKey(entry utils.Analyze) string {
+	return strings.Join([]string{entry.CountryCode, entry.IpDest, entry.Profile.Name, entry.Protocol}, " ")
+}
+
+// failed tells if no HTTP answer was received
+func failed(entry utils.Analyze) bool {
+	return entry.StatusCode == 0
+}
+
+func hidesSNI(probe string) bool {
+	return probe == utils.ProbeNoSNI || probe == utils.ProbeDecoySNI || probe == utils.ProbeECH
+}
+
+func DisplayProbes(notes []string) {
+	if len(notes) == 0 {
+		return
+	}
+
+	fmt.Println("Probes:")
+	for _, note := range notes {
+		fmt.Printf("\t%s\n", note)
+	}
+	fmt.Println("")
+}
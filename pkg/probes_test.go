@@ -0,0 +1,79 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+func TestCompareProbes(t *testing.T) {
+	analyze := func(probe string, status int, err string) utils.Analyze {
+		return utils.Analyze{
+			CountryCode: "CN",
+			IpDest:      "192.0.2.1",
+			Profile:     utils.Profile{Name: "desktop"},
+			Protocol:    utils.ProtocolAuto,
+			Probe:       probe,
+			StatusCode:  status,
+			Status:      utils.StatusFromCode(status),
+			Error:       err,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		data     []utils.Analyze
+		expected []string
+	}{
+		{
+			name: "Same answer",
+			data: []utils.Analyze{
+				analyze("", 200, ""),
+				analyze(utils.ProbeNoSNI, 200, ""),
+				analyze(utils.ProbeHostMismatch, 200, ""),
+			},
+			expected: nil,
+		},
+		{
+			name: "SNI filtering",
+			data: []utils.Analyze{
+				analyze("", 0, "connection reset by peer"),
+				analyze(utils.ProbeNoSNI, 200, ""),
+			},
+			expected: []string{"CN (192.0.2.1): the regular request failed (connection reset by peer) but no-sni got 200, SNI filtering suspected"},
+		},
+		{
+			name: "Probe failing alone",
+			data: []utils.Analyze{
+				analyze("", 200, ""),
+				analyze(utils.ProbeHostMismatch, 0, "EOF"),
+			},
+			expected: []string{"CN (192.0.2.1): sni-host-mismatch failed (EOF) while the regular request got 200"},
+		},
+		{
+			name: "Block not depending on the SNI",
+			data: []utils.Analyze{
+				analyze("", 451, ""),
+				analyze(utils.ProbeDecoySNI, 451, ""),
+			},
+			expected: []string{"CN (192.0.2.1): decoy-sni is answered 451 as well, the block does not depend on the SNI"},
+		},
+		{
+			name: "Probe without regular request",
+			data: []utils.Analyze{
+				analyze(utils.ProbeNoSNI, 200, ""),
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompareProbes(tt.data)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("CompareProbes() got = %q, expected = %q", got, tt.expected)
+			}
+		})
+	}
+}
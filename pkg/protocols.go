@@ -0,0 +1,84 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+// CompareProtocols reports the destinations answering differently depending on the forced protocol,
+// forced protocols the edge refused and the countries where HTTP/3 is advertised or not
+func CompareProtocols(data []utils.Analyze) []string {
+	var notes []string
+	variants := make(map[string][]utils.Analyze)
+	var keys []string
+	h3 := make(map[string]bool)
+
+	for _, entry := range data {
+		if entry.StatusCode == 0 || entry.IsProbe() {
+			continue
+		}
+		where := fmt.Sprintf("%s (%s)", entry.CountryCode, entry.IpDest)
+		if entry.Protocol == utils.ProtocolH2 && !strings.HasPrefix(entry.NegotiatedProtocol, "HTTP/2") {
+			notes = append(notes, fmt.Sprintf("%s does not speak h2 for profile %s, answered with %s",
+				where, entry.Profile.Name, entry.NegotiatedProtocol))
+		}
+
+		key := where + " " + entry.Profile.Name
+		if _, ok := variants[key]; !ok {
+			keys = append(keys, key)
+		}
+		variants[key] = append(variants[key], entry)
+		h3[entry.CountryCode] = h3[entry.CountryCode] || entry.HTTP3
+	}
+
+	for _, key := range keys {
+		entries := variants[key]
+		for _, entry := range entries[1:] {
+			if entry.StatusCode != entries[0].StatusCode || !bytes.Equal(entry.ComparisonHash(), entries[0].ComparisonHash()) {
+				notes = append(notes, fmt.Sprintf("%s answers differently by protocol: %s", key, describeProtocols(entries)))
+				break
+			}
+		}
+	}
+
+	var with, without []string
+	for code, advertised := range h3 {
+		if advertised {
+			with = append(with, code)
+		} else {
+			without = append(without, code)
+		}
+	}
+	if len(with) > 0 && len(without) > 0 {
+		sort.Strings(with)
+		sort.Strings(without)
+		notes = append(notes, fmt.Sprintf("HTTP/3 is advertised with Alt-Svc in %s but not in %s",
+			strings.Join(with, ", "), strings.Join(without, ", ")))
+	}
+
+	return notes
+}
+
+func describeProtocols(entries []utils.Analyze) string {
+	parts := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		parts = append(parts, fmt.Sprintf("%s %s %d %x", entry.Protocol, entry.NegotiatedProtocol, entry.StatusCode, entry.ComparisonHash()))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func DisplayProtocols(notes []string) {
+	if len(notes) == 0 {
+		return
+	}
+
+	fmt.Println("Protocols:")
+	for _, note := range notes {
+		fmt.Printf("\t%s\n", note)
+	}
+	fmt.Println("")
+}
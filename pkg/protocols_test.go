@@ -0,0 +1,87 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+func TestCompareProtocols(t *testing.T) {
+	desktop := utils.Profile{Name: "desktop"}
+	analyze := func(country, protocol, negotiated string, status int, hash string, h3 bool) utils.Analyze {
+		return utils.Analyze{
+			CountryCode:        country,
+			IpDest:             "192.0.2.1",
+			Profile:            desktop,
+			Protocol:           protocol,
+			NegotiatedProtocol: negotiated,
+			StatusCode:         status,
+			Hash:               []byte(hash),
+			HTTP3:              h3,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		data     []utils.Analyze
+		expected []string
+	}{
+		{
+			name: "Same answer for every protocol",
+			data: []utils.Analyze{
+				analyze("FR", utils.ProtocolAuto, "HTTP/2.0", 200, "a", true),
+				analyze("FR", utils.ProtocolH1, "HTTP/1.1", 200, "a", true),
+				analyze("FR", utils.ProtocolH2, "HTTP/2.0", 200, "a", true),
+			},
+			expected: nil,
+		},
+		{
+			name: "h2 refused",
+			data: []utils.Analyze{
+				analyze("FR", utils.ProtocolAuto, "HTTP/1.1", 200, "a", false),
+				analyze("FR", utils.ProtocolH2, "HTTP/1.1", 200, "a", false),
+			},
+			expected: []string{"FR (192.0.2.1) does not speak h2 for profile desktop, answered with HTTP/1.1"},
+		},
+		{
+			name: "Different answer by protocol",
+			data: []utils.Analyze{
+				analyze("FR", utils.ProtocolAuto, "HTTP/2.0", 200, "a", false),
+				analyze("FR", utils.ProtocolH1, "HTTP/1.1", 403, "b", false),
+			},
+			expected: []string{"FR (192.0.2.1) desktop answers differently by protocol: auto HTTP/2.0 200 61, h1 HTTP/1.1 403 62"},
+		},
+		{
+			name: "HTTP/3 advertised in some countries",
+			data: []utils.Analyze{
+				analyze("FR", utils.ProtocolAuto, "HTTP/2.0", 200, "a", true),
+				analyze("DE", utils.ProtocolAuto, "HTTP/2.0", 200, "a", true),
+				analyze("CN", utils.ProtocolAuto, "HTTP/2.0", 200, "a", false),
+			},
+			expected: []string{"HTTP/3 is advertised with Alt-Svc in DE, FR but not in CN"},
+		},
+		{
+			name: "Failed requests and probes skipped",
+			data: func() []utils.Analyze {
+				probe := analyze("FR", utils.ProtocolH2, "HTTP/1.1", 421, "c", false)
+				probe.Probe = utils.ProbeHostMismatch
+				return []utils.Analyze{
+					analyze("FR", utils.ProtocolAuto, "HTTP/2.0", 200, "a", false),
+					analyze("FR", utils.ProtocolH1, "", 0, "", false),
+					probe,
+				}
+			}(),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompareProtocols(tt.data)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("CompareProtocols() got = %q, expected = %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -1,17 +1,29 @@
 package retriever
 
 import (
+	"context"
 	"log"
+	"net/netip"
 
 	"github.com/OnsagerHe/geoip-detector/pkg"
+	"github.com/OnsagerHe/geoip-detector/pkg/cdn"
+	"github.com/OnsagerHe/geoip-detector/pkg/classifier"
 	dnsutils "github.com/OnsagerHe/geoip-detector/pkg/dns"
 	httputils "github.com/OnsagerHe/geoip-detector/pkg/http"
+	"github.com/OnsagerHe/geoip-detector/pkg/normalize"
+	"github.com/OnsagerHe/geoip-detector/pkg/similarity"
+	"github.com/OnsagerHe/geoip-detector/pkg/textdiff"
 	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+	"github.com/OnsagerHe/geoip-detector/pkg/visual"
 	pb "github.com/OnsagerHe/geoip-detector/proto/gen"
 )
 
 type Utils struct {
-	Loop uint8
+	Loop        uint8
+	Sinkholes   []netip.Prefix
+	CDNPrefixes cdn.Prefixes
+	Scenario    *httputils.Scenario
+	Browsers    *httputils.BrowserPool
 }
 
 type Retriever struct {
@@ -28,21 +40,62 @@ func Init(p *utils.GeoIP, loopValue uint8) *Retriever {
 	}
 }
 
-func (p Retriever) CheckEndpoint() (*[]pb.PutEndpointResponse, error) {
+// CheckEndpoint runs a scan, cancelling ctx stops the requests in flight
+func (p Retriever) CheckEndpoint(ctx context.Context) (*pb.PutEndpointResponse, error) {
+	p.resetScan()
 	err := p.initializeResources()
 	if err != nil {
 		log.Printf("Initialization error: %v\n", err)
-		return nil, nil
+		return nil, err
 	}
 
-	p.processRelaysAndDNS()
+	p.Utils.Browsers = httputils.NewBrowserPool(*utils.BrowserWorkers)
+	p.processRelaysAndDNS(ctx)
+	p.Utils.Browsers.Close()
+	p.Process.Steering = dnsutils.ClassifySteering(p.Process.Observations)
+	utils.UniformNormalization(p.Process.Analyzes)
 	utils.CompareHash(p.Process.Analyzes)
 	pkg.SortResult(p.Process.Analyzes)
+	textdiff.AgainstBaseline(p.Process.Analyzes)
+	if *utils.Screenshot {
+		if err := visual.CompareScreenshots(p.Process.Analyzes, *utils.FolderPath, p.Process.Resource, *utils.VisualThreshold); err != nil {
+			log.Printf("Error comparing screenshots: %v\n", err)
+		}
+	}
+	if err := textdiff.WriteArtifacts(p.Process.Analyzes, *utils.FolderPath, p.Process.Resource); err != nil {
+		log.Printf("Error writing diffs: %v\n", err)
+	}
 	if err := p.Process.VPNProvider.SetDefaultDNSResolver(); err != nil {
 		log.Println("Error setting default DNS resolver:", err)
 	}
 
-	return pkg.DisplayInformation(p.Process.Analyzes), nil
+	pkg.DisplaySteering(p.Process.Steering)
+	pkg.DisplayHTTPSRecords(p.Process.Observations)
+	pkg.DisplayZones(p.Process.Zones)
+	pkg.DisplayProviders(p.Process.Analyzes)
+	certificates := pkg.CompareCertificates(p.Process.Analyzes)
+	pkg.DisplayCertificates(certificates)
+	pkg.DisplayTimings(p.Process.Analyzes)
+	pkg.DisplayProtocols(pkg.CompareProtocols(p.Process.Analyzes))
+	pkg.DisplayCrawl(pkg.CompareCrawls(p.Process.Analyzes, *utils.CrawlPages))
+	pkg.DisplayAssets(pkg.CompareAssets(p.Process.Analyzes))
+	pkg.DisplayVerdicts(p.Process.Analyzes)
+	pkg.DisplayProbes(pkg.CompareProbes(p.Process.Analyzes))
+	pkg.DisplayVisual(pkg.CompareVisual(p.Process.Analyzes))
+	matrices := pkg.DisplaySimilarity(similarity.Matrix(p.Process.Analyzes, *utils.SimilarityThreshold))
+
+	response := pkg.DisplayInformation(p.Process.Analyzes)
+	response.Similarity = matrices
+	response.CertificateNotes = certificates
+	return response, nil
+}
+
+// resetScan drops the results of the previous scan, the API server reuses the same process for every call
+func (p Retriever) resetScan() {
+	p.Process.Analyzes = nil
+	p.Process.Observations = nil
+	p.Process.Steering = utils.SteeringReport{}
+	p.Process.Zones = nil
 }
 
 func (p Retriever) initializeResources() error {
@@ -50,19 +103,50 @@ func (p Retriever) initializeResources() error {
 		return err
 	}
 
+	rules, err := normalize.Load(*utils.NormalizationPath)
+	if err != nil {
+		return err
+	}
+	p.Process.Resource.Normalize = normalize.For(rules, p.Process.Resource.Endpoint, p.Process.Resource.Host)
+
+	signatures, err := classifier.Load(*utils.SignaturesPath)
+	if err != nil {
+		return err
+	}
+	p.Process.Resource.Signatures = signatures
+
 	if err := dnsutils.InitNameserversInformation(&p.Process.Resource); err != nil {
 		return err
 	}
 
+	sinkholes, err := dnsutils.LoadSinkholes(*utils.SinkholesPath)
+	if err != nil {
+		return err
+	}
+	p.Utils.Sinkholes = sinkholes
+
+	prefixes, err := cdn.LoadPrefixes(*utils.CDNPrefixesPath)
+	if err != nil {
+		return err
+	}
+	p.Utils.CDNPrefixes = prefixes
+
+	scenario, err := httputils.LoadScenario(*utils.ScenarioPath)
+	if err != nil {
+		return err
+	}
+	p.Utils.Scenario = scenario
+
 	return nil
 }
 
-func (p Retriever) processRelaysAndDNS() {
+func (p Retriever) processRelaysAndDNS(ctx context.Context) {
 	relays := p.Process.VPNProvider.ListVPN()
 	count := uint8(0)
+	crawled := make(map[string]bool)
 
 	for countryCode := range relays {
-		if count >= p.Utils.Loop {
+		if count >= p.Utils.Loop || ctx.Err() != nil {
 			break
 		}
 
@@ -73,6 +157,16 @@ func (p Retriever) processRelaysAndDNS() {
 		}
 
 		count++
+		p.Utils.Browsers.Reset()
+
+		// an IP literal is requested as is, there is no DNS to observe
+		if p.Process.Resource.IsIPLiteral() {
+			hosts := dnsutils.ProcessLiteral(p.Process, countryCode, ips)
+			p.requestHosts(ctx, countryCode, hosts, crawled)
+			continue
+		}
+
+		p.Process.Zones = append(p.Process.Zones, dnsutils.CheckZoneConsistency(p.Process, countryCode))
 
 		for _, ns := range p.Process.Resource.Nameservers {
 			if err := dnsutils.GetIPsNameserver(&ns); err != nil {
@@ -85,21 +179,78 @@ func (p Retriever) processRelaysAndDNS() {
 			// TODO: add debug print with level log
 			//log.Println("nbr ns IPS", ns.IPs)
 			for _, ip := range ns.IPs {
+				dnsutils.SampleNameserver(p.Process, countryCode, ns, ip, *utils.DNSSamples)
 				if err := p.Process.VPNProvider.SetCustomDNSResolver(ip.String()); err != nil {
 					log.Println("Error setting custom DNS resolver:", err)
 					continue
 				}
 				hosts := dnsutils.ProcessDNSRecords(p.Process, countryCode, ips, ns, ip)
-				a := utils.GetAnalyzesByHosts(p.Process.Analyzes, countryCode, hosts)
-				httputils.RequestSpecificEndpoints(p.Process, a)
-				if *utils.Screenshot {
-					httputils.TakeScreenshotByCountryCode(p.Process, a)
-				}
+				p.requestHosts(ctx, countryCode, hosts, crawled)
 			}
 		}
+
+		p.checkDNSIntegrity(countryCode)
 	}
 	if err := p.Process.VPNProvider.SetDefaultDNSResolver(); err != nil {
 		log.Println("Error setting default DNS resolver:", err)
 	}
 	//res.Analyzes = utils.RemoveAnalyzeDuplicates(res.Analyzes)
 }
+
+// requestHosts requests the analyzes of countryCode going to hosts and runs the stages that need the VPN location
+func (p Retriever) requestHosts(ctx context.Context, countryCode string, hosts []string, crawled map[string]bool) {
+	a := utils.GetAnalyzesByHosts(p.Process.Analyzes, countryCode, hosts)
+	httputils.RequestSpecificEndpoints(ctx, p.Process, a)
+	cdn.IdentifyAnalyzes(p.Process, a, p.Utils.CDNPrefixes)
+
+	// the crawl and the browser do not apply the probes, they only go through the regular requests
+	regular := make([]*utils.Analyze, 0, len(a))
+	for _, analyze := range a {
+		if !analyze.IsProbe() {
+			regular = append(regular, analyze)
+		}
+	}
+	if *utils.CrawlDepth > 0 && !crawled[countryCode] {
+		crawled[countryCode] = p.crawl(ctx, regular)
+	}
+	if *utils.Screenshot {
+		httputils.TakeScreenshotByCountryCode(ctx, p.Utils.Browsers, p.Process, regular)
+	}
+	if p.Utils.Scenario != nil {
+		httputils.RunScenarioByCountryCode(ctx, p.Utils.Browsers, p.Process, regular, p.Utils.Scenario)
+	}
+}
+
+// crawl runs the crawl once per country through the first destination that answered
+func (p Retriever) crawl(ctx context.Context, analyzes []*utils.Analyze) bool {
+	for _, analyze := range analyzes {
+		if analyze.StatusCode == 0 {
+			continue
+		}
+		httputils.Crawl(ctx, &p.Process.Resource, analyze, *utils.CrawlDepth, *utils.CrawlPages)
+		return true
+	}
+	return false
+}
+
+// checkDNSIntegrity compares the answers of the resolver given by the VPN in countryCode
+// with the authoritative ones and stores the verdict on every analyze of this country
+func (p Retriever) checkDNSIntegrity(countryCode string) {
+	if err := p.Process.VPNProvider.SetDefaultDNSResolver(); err != nil {
+		log.Println("Error setting default DNS resolver:", err)
+		return
+	}
+
+	resolver, err := dnsutils.VantageResolver()
+	if err != nil {
+		log.Printf("Error getting vantage resolver: %v\n", err)
+		return
+	}
+
+	integrity := dnsutils.CheckIntegrity(p.Process, countryCode, resolver, p.Utils.Sinkholes)
+	for i := range p.Process.Analyzes {
+		if p.Process.Analyzes[i].CountryCode == countryCode {
+			p.Process.Analyzes[i].DNSIntegrity = integrity
+		}
+	}
+}
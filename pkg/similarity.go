@@ -0,0 +1,61 @@
+package pkg
+
+import (
+	"fmt"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/similarity"
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+	pb "github.com/OnsagerHe/geoip-detector/proto/gen"
+)
+
+// DisplaySimilarity prints the text/DOM similarity matrix of each profile and the verdict of every pair of countries,
+// the matrices are returned for the response
+func DisplaySimilarity(matrices []utils.SimilarityMatrix) []*pb.SimilarityMatrix {
+	var messages []*pb.SimilarityMatrix
+	for _, matrix := range matrices {
+		if len(matrix.Countries) < 2 {
+			continue
+		}
+		messages = append(messages, similarityMessage(matrix))
+
+		fmt.Printf("Similarity for profile %s (text/dom, threshold %.2f):\n\t", matrix.Profile, matrix.Threshold)
+		for _, code := range matrix.Countries {
+			fmt.Printf("%-11s", code)
+		}
+		fmt.Println("")
+		for i, code := range matrix.Countries {
+			fmt.Printf("%s\t", code)
+			for j := range matrix.Countries {
+				fmt.Printf("%.2f/%.2f  ", matrix.Text[i][j], matrix.DOM[i][j])
+			}
+			fmt.Println("")
+		}
+
+		for i := range matrix.Countries {
+			for j := i + 1; j < len(matrix.Countries); j++ {
+				fmt.Printf("\t%s - %s: %s\n", matrix.Countries[i], matrix.Countries[j], similarity.Verdict(matrix, i, j))
+			}
+		}
+		fmt.Println("")
+	}
+	return messages
+}
+
+func similarityMessage(matrix utils.SimilarityMatrix) *pb.SimilarityMatrix {
+	message := &pb.SimilarityMatrix{
+		Profile:   matrix.Profile,
+		Countries: matrix.Countries,
+		Threshold: matrix.Threshold,
+	}
+	for i := range matrix.Countries {
+		message.Rows = append(message.Rows, &pb.SimilarityRow{Text: matrix.Text[i], Dom: matrix.DOM[i]})
+		for j := i + 1; j < len(matrix.Countries); j++ {
+			message.Pairs = append(message.Pairs, &pb.SimilarityPair{
+				CountryA: matrix.Countries[i],
+				CountryB: matrix.Countries[j],
+				Verdict:  similarity.Verdict(matrix, i, j),
+			})
+		}
+	}
+	return message
+}
@@ -0,0 +1,141 @@
+package similarity
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"sort"
+	"strings"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/page"
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+	"golang.org/x/net/html"
+)
+
+const shingleSize = 3
+
+const (
+	NearIdentical          = "near-identical"
+	SubstantivelyDifferent = "substantively different"
+)
+
+// Simhash folds the features into a 64 bit locality-sensitive fingerprint,
+// close documents give fingerprints with a small Hamming distance
+func Simhash(features []string) uint64 {
+	var weights [64]int
+	for _, feature := range features {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+		for i := 0; i < 64; i++ {
+			if sum&(1<<uint(i)) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for i, weight := range weights {
+		if weight > 0 {
+			fingerprint |= 1 << uint(i)
+		}
+	}
+	return fingerprint
+}
+
+// Similarity is 1 for equal fingerprints and 0 when every bit differs
+func Similarity(a, b uint64) float64 {
+	return 1 - float64(bits.OnesCount64(a^b))/64
+}
+
+// Shingles returns the overlapping word sequences of text
+func Shingles(text string) []string {
+	words := strings.Fields(strings.ToLower(text))
+	if len(words) < shingleSize {
+		return words
+	}
+
+	shingles := make([]string, 0, len(words)-shingleSize+1)
+	for i := 0; i+shingleSize <= len(words); i++ {
+		shingles = append(shingles, strings.Join(words[i:i+shingleSize], " "))
+	}
+	return shingles
+}
+
+// Fingerprint computes the text simhash over visible text shingles and the structural simhash over tag paths
+func Fingerprint(doc *html.Node) *utils.Fingerprint {
+	return &utils.Fingerprint{
+		Text: Simhash(Shingles(page.VisibleText(doc))),
+		DOM:  Simhash(page.TagPaths(doc)),
+	}
+}
+
+// Matrix compares every pair of countries for each profile, the similarity of two
+// countries is the mean similarity between their analyzes
+func Matrix(data []utils.Analyze, threshold float64) []utils.SimilarityMatrix {
+	byProfile := make(map[string]map[string][]*utils.Fingerprint)
+	for _, entry := range data {
+		if entry.Fingerprint == nil || entry.IsProbe() {
+			continue
+		}
+		profile := entry.Profile.Name
+		if byProfile[profile] == nil {
+			byProfile[profile] = make(map[string][]*utils.Fingerprint)
+		}
+		byProfile[profile][entry.CountryCode] = append(byProfile[profile][entry.CountryCode], entry.Fingerprint)
+	}
+
+	profiles := make([]string, 0, len(byProfile))
+	for profile := range byProfile {
+		profiles = append(profiles, profile)
+	}
+	sort.Strings(profiles)
+
+	var matrices []utils.SimilarityMatrix
+	for _, profile := range profiles {
+		countries := make([]string, 0, len(byProfile[profile]))
+		for code := range byProfile[profile] {
+			countries = append(countries, code)
+		}
+		sort.Strings(countries)
+
+		matrix := utils.SimilarityMatrix{
+			Profile:   profile,
+			Countries: countries,
+			Threshold: threshold,
+			Text:      make([][]float64, len(countries)),
+			DOM:       make([][]float64, len(countries)),
+		}
+		for i, a := range countries {
+			matrix.Text[i] = make([]float64, len(countries))
+			matrix.DOM[i] = make([]float64, len(countries))
+			for j, b := range countries {
+				matrix.Text[i][j], matrix.DOM[i][j] = meanSimilarity(byProfile[profile][a], byProfile[profile][b])
+			}
+		}
+		matrices = append(matrices, matrix)
+	}
+
+	return matrices
+}
+
+func meanSimilarity(a, b []*utils.Fingerprint) (float64, float64) {
+	var text, dom float64
+	for _, x := range a {
+		for _, y := range b {
+			text += Similarity(x.Text, y.Text)
+			dom += Similarity(x.DOM, y.DOM)
+		}
+	}
+	n := float64(len(a) * len(b))
+	return text / n, dom / n
+}
+
+// Verdict tells if two countries got near-identical pages, text and structure both have to pass the threshold
+func Verdict(matrix utils.SimilarityMatrix, i, j int) string {
+	if matrix.Text[i][j] >= matrix.Threshold && matrix.DOM[i][j] >= matrix.Threshold {
+		return NearIdentical
+	}
+	return SubstantivelyDifferent
+}
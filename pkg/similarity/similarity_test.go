@@ -0,0 +1,49 @@
+package similarity
+
+import (
+	"testing"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/page"
+)
+
+func TestFingerprint(t *testing.T) {
+	base := `<html><head><title>Shop</title><script>var nonce = "a1";</script></head><body><div><h1>Welcome to the shop</h1><p>Free delivery on every order placed before noon, returns are accepted for thirty days after the purchase.</p><p>Our support team answers every day of the week.</p></div></body></html>`
+
+	tests := []struct {
+		name    string
+		other   string
+		similar bool
+	}{
+		{
+			name:    "Script change only",
+			other:   `<html><head><title>Shop</title><script>var nonce = "b2";</script></head><body><div><h1>Welcome to the shop</h1><p>Free delivery on every order placed before noon, returns are accepted for thirty days after the purchase.</p><p>Our support team answers every day of the week.</p></div></body></html>`,
+			similar: true,
+		},
+		{
+			name:    "Block page",
+			other:   `<html><head><title>Unavailable</title></head><body><table><tr><td><span>This content is not available in your country due to legal restrictions.</span></td></tr></table><footer>Reference 4451</footer></body></html>`,
+			similar: false,
+		},
+	}
+
+	doc, err := page.Parse([]byte(base))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	a := Fingerprint(doc)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := page.Parse([]byte(tt.other))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			b := Fingerprint(doc)
+			text, dom := Similarity(a.Text, b.Text), Similarity(a.DOM, b.DOM)
+			similar := text >= 0.9 && dom >= 0.9
+			if similar != tt.similar {
+				t.Errorf("Fingerprint() text = %.2f, dom = %.2f, expected similar = %v", text, dom, tt.similar)
+			}
+		})
+	}
+}
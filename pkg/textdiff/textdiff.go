@@ -0,0 +1,165 @@
+package textdiff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	opEqual = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind int
+	// position in a and in b when the op happens
+	i, j int
+	line string
+}
+
+// Unified returns the unified diff of the lines of a and b with context lines
+// around each change, or an empty string when they are equal. When the changed part
+// is too large the diff only says so
+func Unified(fromName, toName string, a, b []string, context int) string {
+	ops, err := edits(a, b)
+	if err != nil {
+		return fmt.Sprintf("--- %s\n+++ %s\n# %v\n", fromName, toName, err)
+	}
+
+	var changes []int
+	for k, o := range ops {
+		if o.kind != opEqual {
+			changes = append(changes, k)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	for c := 0; c < len(changes); {
+		start := max(0, changes[c]-context)
+		last := changes[c]
+		c++
+		// changes closer than twice the context share a hunk
+		for c < len(changes) && changes[c]-last <= 2*context {
+			last = changes[c]
+			c++
+		}
+		end := min(len(ops), last+context+1)
+		writeHunk(&sb, ops[start:end])
+	}
+
+	return sb.String()
+}
+
+func writeHunk(sb *strings.Builder, ops []op) {
+	var aCount, bCount int
+	for _, o := range ops {
+		if o.kind != opInsert {
+			aCount++
+		}
+		if o.kind != opDelete {
+			bCount++
+		}
+	}
+
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(ops[0].i, aCount), hunkRange(ops[0].j, bCount))
+	for _, o := range ops {
+		switch o.kind {
+		case opEqual:
+			sb.WriteString(" ")
+		case opDelete:
+			sb.WriteString("-")
+		case opInsert:
+			sb.WriteString("+")
+		}
+		sb.WriteString(o.line)
+		sb.WriteString("\n")
+	}
+}
+
+// hunkRange is 1-based, an empty range points to the line before it
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// maxCells bounds the LCS table of the lines left once the common prefix and suffix are removed,
+// 16 MiB of int32, larger changes are not diffed
+const maxCells = 1 << 22
+
+// errTooLarge is returned when the changed part of two contents is too large to diff
+var errTooLarge = errors.New("too large to diff")
+
+// edits turns a into b with the longest common subsequence of lines kept as is
+func edits(a, b []string) ([]op, error) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	n, m := len(a)-prefix-suffix, len(b)-prefix-suffix
+	if (n+1)*(m+1) > maxCells {
+		return nil, fmt.Errorf("%w: %d and %d changed lines", errTooLarge, n, m)
+	}
+
+	var ops []op
+	for k := 0; k < prefix; k++ {
+		ops = append(ops, op{kind: opEqual, i: k, j: k, line: a[k]})
+	}
+	ops = append(ops, middle(a[prefix:prefix+n], b[prefix:prefix+m], prefix)...)
+	for k := suffix; k > 0; k-- {
+		ops = append(ops, op{kind: opEqual, i: len(a) - k, j: len(b) - k, line: a[len(a)-k]})
+	}
+	return ops, nil
+}
+
+// middle diffs the changed part of a and b, offset is where it starts in both
+func middle(a, b []string, offset int) []op {
+	n, m := len(a), len(b)
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []op
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && a[i] == b[j]:
+			ops = append(ops, op{kind: opEqual, i: offset + i, j: offset + j, line: a[i]})
+			i++
+			j++
+		case i < n && (j == m || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, op{kind: opDelete, i: offset + i, j: offset + j, line: a[i]})
+			i++
+		default:
+			ops = append(ops, op{kind: opInsert, i: offset + i, j: offset + j, line: b[j]})
+			j++
+		}
+	}
+	return ops
+}
@@ -0,0 +1,80 @@
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        []string
+		b        []string
+		expected string
+	}{
+		{
+			name:     "Equal",
+			a:        []string{"title: Shop", "Welcome"},
+			b:        []string{"title: Shop", "Welcome"},
+			expected: "",
+		},
+		{
+			name: "Changed title",
+			a:    []string{"title: Shop", "lang: en", "Welcome", "Prices", "Contact"},
+			b:    []string{"title: Unavailable", "lang: en", "Welcome", "Prices", "Contact"},
+			expected: "--- FR\n+++ CN\n" +
+				"@@ -1,4 +1,4 @@\n-title: Shop\n+title: Unavailable\n lang: en\n Welcome\n Prices\n",
+		},
+		{
+			name:     "Added line",
+			a:        []string{"Welcome"},
+			b:        []string{"Welcome", "Not available in your region"},
+			expected: "--- FR\n+++ CN\n@@ -1 +1,2 @@\n Welcome\n+Not available in your region\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified("FR", "CN", tt.a, tt.b, 3)
+			if got != tt.expected {
+				t.Errorf("Unified() got = %q, expected = %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnifiedTooLarge(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 3000; i++ {
+		a = append(a, fmt.Sprintf("old %d", i))
+		b = append(b, fmt.Sprintf("new %d", i))
+	}
+	a = append([]string{"same"}, a...)
+	b = append([]string{"same"}, b...)
+
+	got := Unified("FR", "CN", a, b, 3)
+	expected := "--- FR\n+++ CN\n# too large to diff: 3000 and 3000 changed lines\n"
+	if got != expected {
+		t.Errorf("Unified() got = %q, expected = %q", got, expected)
+	}
+}
+
+func TestAgainstBaseline(t *testing.T) {
+	data := []utils.Analyze{
+		{CountryCode: "FR", Hash: []byte("a"), Content: []string{"Welcome"}},
+		{CountryCode: "FR", Hash: []byte("404"), Content: []string{"Not found"}, Probe: utils.ProbeHostMismatch},
+		{CountryCode: "DE", Hash: []byte("404"), Content: []string{"Not found"}, Probe: utils.ProbeHostMismatch},
+		{CountryCode: "CN", Hash: []byte("b"), Content: []string{"Unavailable"}},
+	}
+	AgainstBaseline(data)
+
+	if data[1].Diff != "" || data[2].Diff != "" {
+		t.Errorf("AgainstBaseline() diffed a probe: %q %q", data[1].Diff, data[2].Diff)
+	}
+	if data[3].Diff == "" || !strings.Contains(data[3].Diff, "baseline FR") {
+		t.Errorf("AgainstBaseline() got = %q, expected a diff against FR", data[3].Diff)
+	}
+}
@@ -0,0 +1,76 @@
+package textdiff
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+const contextLines = 3
+
+// AgainstBaseline diffs the content of every analyze with the baseline variant of its profile,
+// the baseline being the most frequent comparison hash
+func AgainstBaseline(data []utils.Analyze) {
+	baselines := make(map[string]int)
+	for profile, hash := range mostFrequentHashes(data) {
+		for i := range data {
+			if data[i].Profile.Name == profile && data[i].Content != nil && !data[i].IsProbe() && bytes.Equal(data[i].ComparisonHash(), hash) {
+				baselines[profile] = i
+				break
+			}
+		}
+	}
+
+	for i := range data {
+		b, ok := baselines[data[i].Profile.Name]
+		if !ok || data[i].Content == nil || data[i].IsProbe() || bytes.Equal(data[i].ComparisonHash(), data[b].ComparisonHash()) {
+			continue
+		}
+		base := data[b]
+		data[i].Diff = Unified(
+			fmt.Sprintf("baseline %s %s", base.CountryCode, base.IpDest),
+			fmt.Sprintf("%s %s", data[i].CountryCode, data[i].IpDest),
+			base.Content, data[i].Content, contextLines)
+	}
+}
+
+func mostFrequentHashes(data []utils.Analyze) map[string][]byte {
+	count := make(map[string]map[string]int)
+	best := make(map[string][]byte)
+	for _, entry := range data {
+		if entry.Content == nil || entry.IsProbe() {
+			continue
+		}
+		profile, hash := entry.Profile.Name, entry.ComparisonHash()
+		if count[profile] == nil {
+			count[profile] = make(map[string]int)
+		}
+		count[profile][string(hash)]++
+		if _, ok := best[profile]; !ok || count[profile][string(hash)] > count[profile][string(best[profile])] {
+			best[profile] = hash
+		}
+	}
+	return best
+}
+
+// WriteArtifacts saves every non empty diff in folder and records its filename on the analyze
+func WriteArtifacts(data []utils.Analyze, folder string, resource utils.EndpointMetadata) error {
+	if err := os.MkdirAll(folder, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create folder: %w", err)
+	}
+
+	for i := range data {
+		if data[i].Diff == "" {
+			continue
+		}
+		fileName := resource.ArtifactName("diff", data[i].CountryCode, data[i].Profile.Name, fmt.Sprintf("%x", data[i].ComparisonHash()))
+		if err := os.WriteFile(filepath.Join(folder, fileName), []byte(data[i].Diff), 0644); err != nil {
+			return err
+		}
+		data[i].DiffFilename = fileName
+	}
+	return nil
+}
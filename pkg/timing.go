@@ -0,0 +1,108 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+	"github.com/fatih/color"
+)
+
+// a group whose median time to first byte is this many times the overall one is likely served from far away
+const slowFactor = 2
+
+// TimingSummary holds the median timings of the analyzes sharing a country or a destination IP
+type TimingSummary struct {
+	Key     string
+	Count   int
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+	TTFB    time.Duration
+	Total   time.Duration
+}
+
+// AggregateTimings groups the timed analyzes with key and keeps the medians of each group
+func AggregateTimings(data []utils.Analyze, key func(utils.Analyze) string) []TimingSummary {
+	groups := make(map[string][]*utils.Timing)
+	for _, entry := range data {
+		if entry.Timing == nil || entry.IsProbe() {
+			continue
+		}
+		groups[key(entry)] = append(groups[key(entry)], entry.Timing)
+	}
+
+	summaries := make([]TimingSummary, 0, len(groups))
+	for k, timings := range groups {
+		pick := func(field func(*utils.Timing) time.Duration) time.Duration {
+			values := make([]time.Duration, len(timings))
+			for i, timing := range timings {
+				values[i] = field(timing)
+			}
+			return median(values)
+		}
+		summaries = append(summaries, TimingSummary{
+			Key:     k,
+			Count:   len(timings),
+			DNS:     pick(func(t *utils.Timing) time.Duration { return t.DNS }),
+			Connect: pick(func(t *utils.Timing) time.Duration { return t.Connect }),
+			TLS:     pick(func(t *utils.Timing) time.Duration { return t.TLS }),
+			TTFB:    pick(func(t *utils.Timing) time.Duration { return t.TTFB }),
+			Total:   pick(func(t *utils.Timing) time.Duration { return t.Total }),
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Key < summaries[j].Key
+	})
+	return summaries
+}
+
+func median(values []time.Duration) time.Duration {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
+
+// DisplayTimings prints the median timings per country and per destination IP,
+// groups much slower than the others are highlighted
+func DisplayTimings(data []utils.Analyze) {
+	var all []time.Duration
+	for _, entry := range data {
+		if entry.Timing != nil && !entry.IsProbe() {
+			all = append(all, entry.Timing.TTFB)
+		}
+	}
+	if len(all) == 0 {
+		return
+	}
+	overall := median(all)
+
+	groupings := []struct {
+		title string
+		key   func(utils.Analyze) string
+	}{
+		{"country", func(a utils.Analyze) string { return a.CountryCode }},
+		{"destination IP", func(a utils.Analyze) string { return a.IpDest }},
+	}
+
+	for _, grouping := range groupings {
+		fmt.Printf("Timings per %s (median):\n", grouping.title)
+		for _, s := range AggregateTimings(data, grouping.key) {
+			line := fmt.Sprintf("\t%s: dns %s connect %s tls %s ttfb %s total %s (%d requests)",
+				s.Key, s.DNS, s.Connect, s.TLS, s.TTFB, s.Total, s.Count)
+			if overall > 0 && s.TTFB > slowFactor*overall {
+				line = color.YellowString("%s, slower than the median ttfb %s", line, overall)
+			}
+			fmt.Println(line)
+		}
+		fmt.Println("")
+	}
+}
@@ -0,0 +1,52 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []time.Duration
+		expected time.Duration
+	}{
+		{name: "Empty", values: nil, expected: 0},
+		{name: "Odd count", values: []time.Duration{30, 10, 20}, expected: 20},
+		{name: "Even count", values: []time.Duration{40, 10, 20, 30}, expected: 25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := median(tt.values); got != tt.expected {
+				t.Errorf("median() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAggregateTimings(t *testing.T) {
+	timed := func(country string, ttfb time.Duration, probe string) utils.Analyze {
+		return utils.Analyze{CountryCode: country, Probe: probe, Timing: &utils.Timing{DNS: 5, TTFB: ttfb, Total: 2 * ttfb}}
+	}
+	data := []utils.Analyze{
+		timed("fr", 10, ""),
+		timed("fr", 30, ""),
+		timed("fr", 20, ""),
+		timed("jp", 100, ""),
+		timed("jp", 900, utils.ProbeNoSNI),
+		{CountryCode: "us"},
+	}
+
+	got := AggregateTimings(data, func(a utils.Analyze) string { return a.CountryCode })
+	expected := []TimingSummary{
+		{Key: "fr", Count: 3, DNS: 5, TTFB: 20, Total: 40},
+		{Key: "jp", Count: 1, DNS: 5, TTFB: 100, Total: 200},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("AggregateTimings() = %+v, expected %+v", got, expected)
+	}
+}
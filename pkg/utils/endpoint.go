@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/netip"
+	"regexp"
+	"strings"
+)
+
+// artifact names are capped below the usual 255 bytes limit of filesystems
+const maxArtifactName = 200
+
+// the part of the host kept when a name is shortened, below it the name is hashed instead
+const minArtifactPrefix = 16
+
+var unsafeArtifactChars = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// IsIPLiteral tells if the endpoint host is an IPv4 or IPv6 address rather than a name
+func (e EndpointMetadata) IsIPLiteral() bool {
+	_, err := netip.ParseAddr(e.Host)
+	return err == nil
+}
+
+// ArtifactName builds a file name from the endpoint host, its port when it is not the default one
+// and parts, every character outside [A-Za-z0-9.-] is replaced so that IPv6 hosts or profile names are safe.
+// A name too long keeps its parts, which tell the artifacts apart, and loses the end of the host, when
+// the parts alone are too long their overflow is replaced by a short hash of the whole name
+func (e EndpointMetadata) ArtifactName(ext string, parts ...string) string {
+	prefix := e.Host
+	if e.Port != "" && e.Port != DefaultPort(e.Scheme) {
+		prefix += "_" + e.Port
+	}
+	prefix = strings.Trim(unsafeArtifactChars.ReplaceAllString(prefix, "_"), "_")
+	suffix := strings.TrimRight(unsafeArtifactChars.ReplaceAllString("_"+strings.Join(parts, "_"), "_"), "_")
+
+	name := prefix + suffix
+	if len(name) > maxArtifactName {
+		if keep := maxArtifactName - len(suffix); keep >= minArtifactPrefix {
+			name = prefix[:keep] + suffix
+		} else {
+			sum := sha256.Sum256([]byte(name))
+			name = name[:maxArtifactName-9] + "_" + hex.EncodeToString(sum[:4])
+		}
+	}
+	return strings.Trim(name, "_") + "." + ext
+}
+
+// DefaultPort is the port of scheme when the URL does not give one
+func DefaultPort(scheme string) string {
+	switch scheme {
+	case "https":
+		return "443"
+	case "http":
+		return "80"
+	}
+	return ""
+}
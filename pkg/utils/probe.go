@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Probes are extra requests sent to every destination to tell SNI filtering from origin-side blocking
+const (
+	// ProbeHostMismatch keeps the real SNI and sends the decoy as Host
+	ProbeHostMismatch = "sni-host-mismatch"
+	// ProbeNoSNI requests the IP literal, no SNI is sent, with the real Host
+	ProbeNoSNI = "no-sni"
+	// ProbeDecoySNI sends the decoy as SNI with the real Host
+	ProbeDecoySNI = "decoy-sni"
+	// ProbeECH hides the real SNI in an Encrypted Client Hello, only when HTTPS records advertise one
+	ProbeECH = "ech"
+)
+
+// ParseProbes reads the comma separated list of probes sent to every destination
+func ParseProbes(value string) ([]string, error) {
+	var probes []string
+	seen := make(map[string]bool)
+	for _, probe := range strings.Split(value, ",") {
+		probe = strings.ToLower(strings.TrimSpace(probe))
+		if probe == "" || seen[probe] {
+			continue
+		}
+		switch probe {
+		case ProbeHostMismatch, ProbeNoSNI, ProbeDecoySNI, ProbeECH:
+		default:
+			return nil, fmt.Errorf("unknown probe %q, expected %s, %s, %s or %s", probe, ProbeHostMismatch, ProbeNoSNI, ProbeDecoySNI, ProbeECH)
+		}
+		seen[probe] = true
+		probes = append(probes, probe)
+	}
+	return probes, nil
+}
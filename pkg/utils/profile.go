@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+)
+
+const DefaultProfileName = "default"
+
+// Profile describes how a request is sent, a scan runs every profile from every country
+type Profile struct {
+	Name           string            `json:"name"`
+	Method         string            `json:"method"`
+	Headers        map[string]string `json:"headers"`
+	UserAgent      string            `json:"user_agent"`
+	AcceptLanguage string            `json:"accept_language"`
+	Cookies        map[string]string `json:"cookies"`
+	Body           string            `json:"body"`
+}
+
+// DefaultProfiles is a bare GET, as sent before profiles existed
+func DefaultProfiles() []Profile {
+	return []Profile{{Name: DefaultProfileName, Method: "GET"}}
+}
+
+// LoadProfiles reads a JSON array of profiles, the default profile is used when path is empty
+func LoadProfiles(path string) ([]Profile, error) {
+	if path == "" {
+		return DefaultProfiles(), nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []Profile
+	if err := json.Unmarshal(content, &profiles); err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, errors.New("no profile defined in " + path)
+	}
+
+	if err := ValidateProfiles(profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// ValidateProfiles checks that every profile has a unique name, results are keyed by it, and sets the default method
+func ValidateProfiles(profiles []Profile) error {
+	names := make(map[string]bool)
+	for i := range profiles {
+		if profiles[i].Name == "" {
+			return errors.New("every profile needs a name")
+		}
+		if names[profiles[i].Name] {
+			return errors.New("duplicate profile " + profiles[i].Name)
+		}
+		names[profiles[i].Name] = true
+		if profiles[i].Method == "" {
+			profiles[i].Method = "GET"
+		}
+	}
+	return nil
+}
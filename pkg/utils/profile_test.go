@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []Profile
+		expected []Profile
+		hasError bool
+	}{
+		{
+			name:     "Default method",
+			input:    []Profile{{Name: "desktop"}, {Name: "api", Method: "POST"}},
+			expected: []Profile{{Name: "desktop", Method: "GET"}, {Name: "api", Method: "POST"}},
+		},
+		{
+			name:     "Empty name",
+			input:    []Profile{{Name: "desktop"}, {Method: "GET"}},
+			hasError: true,
+		},
+		{
+			name:     "Duplicate name",
+			input:    []Profile{{Name: "desktop"}, {Name: "desktop", UserAgent: "Mobile"}},
+			hasError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateProfiles(tt.input)
+			if (err != nil) != tt.hasError {
+				t.Fatalf("ValidateProfiles() error = %v, expected error = %v", err, tt.hasError)
+			}
+			if !tt.hasError && !reflect.DeepEqual(tt.input, tt.expected) {
+				t.Errorf("ValidateProfiles() got = %+v, expected = %+v", tt.input, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// ProtocolAuto lets the transport negotiate, as before protocols could be forced
+	ProtocolAuto = "auto"
+	ProtocolH1   = "h1"
+	ProtocolH2   = "h2"
+)
+
+// ParseProtocols reads the comma separated list of protocols to force on every destination
+func ParseProtocols(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return []string{ProtocolAuto}, nil
+	}
+
+	var protocols []string
+	seen := make(map[string]bool)
+	for _, proto := range strings.Split(value, ",") {
+		proto = strings.ToLower(strings.TrimSpace(proto))
+		switch proto {
+		case ProtocolAuto, ProtocolH1, ProtocolH2:
+		default:
+			return nil, fmt.Errorf("unknown protocol %q, expected %s, %s or %s", proto, ProtocolAuto, ProtocolH1, ProtocolH2)
+		}
+		if !seen[proto] {
+			seen[proto] = true
+			protocols = append(protocols, proto)
+		}
+	}
+	return protocols, nil
+}
@@ -3,10 +3,16 @@ package utils
 import (
 	"bytes"
 	"fmt"
+	"hash"
 	"log"
 	"net"
+	"net/http"
 	"strings"
+	"time"
 
+	"github.com/OnsagerHe/geoip-detector/pkg/classifier"
+	"github.com/OnsagerHe/geoip-detector/pkg/emulation"
+	"github.com/OnsagerHe/geoip-detector/pkg/normalize"
 	"github.com/OnsagerHe/geoip-detector/pkg/vpn"
 
 	"go.uber.org/zap"
@@ -18,12 +24,41 @@ var BrowserPath *string
 var Screenshot *bool
 var Source *bool
 var Prd *bool
+var DNSSamples *uint
+var Resolver *string
+var SinkholesPath *string
+var CDNPrefixesPath *string
+var MaxRedirects *uint
+var ProfilesPath *string
+var NormalizationPath *string
+var SimilarityThreshold *float64
+var Protocols *string
+var CrawlDepth *uint
+var CrawlPages *uint
+var Subresources *uint
+var SignaturesPath *string
+var RequestTimeout *time.Duration
+var MaxBody *int64
+var Retries *uint
+var Probes *string
+var DecoySNI *string
+var ScenarioPath *string
+var BrowserWorkers *uint
+var VisualThreshold *float64
+var Emulate *string
 
 type GeoIP struct {
-	Resource    EndpointMetadata
-	Analyzes    []Analyze
-	VPNProvider vpn.IProviderVPN
-	Logger      *zap.Logger
+	Resource     EndpointMetadata
+	Analyzes     []Analyze
+	Profiles     []Profile
+	Protocols    []string
+	Probes       []string
+	Emulation    emulation.Mode
+	Observations []DNSObservation
+	Steering     SteeringReport
+	Zones        []ZoneReport
+	VPNProvider  vpn.IProviderVPN
+	Logger       *zap.Logger
 }
 
 type Nameserver struct {
@@ -31,30 +66,328 @@ type Nameserver struct {
 	IPs  []net.IP
 }
 
+// EndpointMetadata is the endpoint to scan, Endpoint is the normalized URL without userinfo,
+// Host is the ASCII host name or IP without brackets and Port is always set, e.g. "443"
 type EndpointMetadata struct {
 	Endpoint    string
+	Scheme      string
 	Port        string
-	Prefix      string
 	Host        string
 	Nameservers []Nameserver
+	Zone        string
 	Cname       bool
 	CnameHost   string
+	CnameChain  []string
+	Normalize   *normalize.Rules
+	Signatures  []classifier.Signature
 	Online      bool
 }
 
 type Analyze struct {
-	IpDest      string
-	IpSource    []string
+	IpDest         string
+	IpSource       []string
+	CountryCode    string
+	Profile        Profile
+	Hash           []byte
+	NormalizedHash []byte
+	// NormalizeError is set when the normalization rules failed on the body
+	NormalizeError string
+	Status         string
+	StatusCode     int
+	ContentType    string
+	BodySize       int64
+	Nameserver     Nameserver
+	Filename       string
+	DNSIntegrity   DNSIntegrity
+	Headers        http.Header
+	Provider       Provider
+	ALPN           []string
+	FromHint       bool
+	Redirects      []RedirectHop
+	RedirectClass  string
+	TLS            *TLSInfo
+	Fingerprint    *Fingerprint
+	Content        []string
+	Diff           string
+	DiffFilename   string
+	Timing         *Timing
+	// Resolution is how long the nameserver of the analyze took to resolve the endpoint host
+	Resolution time.Duration
+	Protocol   string
+	// NegotiatedProtocol is the protocol of the final response, e.g. HTTP/2.0
+	NegotiatedProtocol string
+	AltSvc             []string
+	HTTP3              bool
+	Pages              []Page
+	Assets             []Asset
+	Verdict            classifier.Verdict
+	// Truncated is set when the body went beyond the maximum size, only its hash covers all of it
+	Truncated bool
+	Attempts  int
+	Error     string
+	// Probe is empty for the regular request
+	Probe     string
+	ECHConfig []byte
+	Steps     []StepResult
+	// Screenshot is the file name of the screenshot, Filename may be the saved source
+	Screenshot string
+	Visual     *VisualDiff
+	// Emulation is what the browser reported for the screenshot and the scenario
+	Emulation *emulation.Settings
+}
+
+const (
+	StatusUnreachable = "unreachable"
+	StatusOnline      = "online"
+	StatusRedirect    = "redirect"
+	StatusBlocked     = "blocked"
+	StatusNotFound    = "not-found"
+	StatusRateLimited = "rate-limited"
+	StatusLegal       = "unavailable-for-legal-reasons"
+	StatusClientError = "client-error"
+	StatusServerError = "server-error"
+)
+
+// StatusFromCode maps an HTTP status code to the status of an analyze
+func StatusFromCode(code int) string {
+	switch {
+	case code >= 200 && code < 300:
+		return StatusOnline
+	case code >= 300 && code < 400:
+		return StatusRedirect
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return StatusBlocked
+	case code == http.StatusNotFound || code == http.StatusGone:
+		return StatusNotFound
+	case code == http.StatusTooManyRequests:
+		return StatusRateLimited
+	case code == http.StatusUnavailableForLegalReasons:
+		return StatusLegal
+	case code >= 400 && code < 500:
+		return StatusClientError
+	case code >= 500:
+		return StatusServerError
+	default:
+		return StatusUnreachable
+	}
+}
+
+// DNSObservation holds the answer sets returned by one nameserver IP
+// when it is queried several times from the same country
+type DNSObservation struct {
 	CountryCode string
+	Nameserver  string
+	Server      net.IP
+	Samples     uint
+	NXDomain    uint
+	AnswerSets  map[string]uint
+	HTTPS       []SVCBRecord
+}
+
+// SVCBRecord is a parsed HTTPS (type 65) record
+type SVCBRecord struct {
+	Priority      uint16
+	Target        string
+	ALPN          []string
+	NoDefaultALPN bool
+	Port          uint16
+	IPv4Hint      []string
+	IPv6Hint      []string
+	ECH           []byte
+}
+
+type CountrySteering struct {
+	Behaviour  string
+	Confidence float64
+	Samples    uint
+	AnswerSets int
+}
+
+// SteeringReport is the classification of the zone's steering behaviour
+type SteeringReport struct {
+	Behaviour string
+	Countries map[string]CountrySteering
+}
+
+// DNSIntegrity compares what the vantage resolver answered with the authoritative answers
+type DNSIntegrity struct {
+	Verdict       string
+	Flags         []string
+	Resolver      string
+	Answers       []string
+	Authoritative []string
+	Responses     int
+}
+
+// ZoneServer is what one authoritative nameserver IP answered for the zone SOA and the target name
+type ZoneServer struct {
+	Nameserver string
+	IP         net.IP
+	Serial     uint32
+	Answers    []string
+	Error      string
+}
+
+// ZoneReport lists the nameserver IPs lagging behind or disagreeing with the others, seen from one country
+type ZoneReport struct {
+	CountryCode    string
+	Zone           string
+	Servers        []ZoneServer
+	Unsynchronised []string
+	Diverging      []string
+	Notes          []string
+}
+
+// RedirectHop is one response of a redirect chain, the last hop is the final response
+type RedirectHop struct {
+	URL        string
+	StatusCode int
+	Location   string
+	SetCookie  []string
+	Duration   time.Duration
+}
+
+const (
+	OCSPNone    = "none"
+	OCSPGood    = "good"
+	OCSPRevoked = "revoked"
+	OCSPUnknown = "unknown"
+	OCSPExpired = "expired"
+	OCSPInvalid = "invalid"
+)
+
+type Certificate struct {
+	Subject   string
+	SANs      []string
+	Issuer    string
+	Serial    string
+	NotBefore time.Time
+	NotAfter  time.Time
+	SPKIHash  string
+}
+
+// TLSInfo is the handshake of the final response, Chain starts with the leaf certificate
+type TLSInfo struct {
+	Version     string
+	CipherSuite string
+	ALPN        string
+	ServerName  string
+	Chain       []Certificate
+	OCSPStatus  string
+	VerifyError string
+}
+
+// Fingerprint holds the simhash of the visible text and of the DOM structure of a body
+type Fingerprint struct {
+	Text uint64
+	DOM  uint64
+}
+
+// SimilarityMatrix is the mean text and DOM similarity between every pair of countries for one profile
+type SimilarityMatrix struct {
+	Profile   string
+	Countries []string
+	Threshold float64
+	Text      [][]float64
+	DOM       [][]float64
+}
+
+// Timing is the breakdown of the final request, Download runs from the first byte to the end of the body.
+// DNS comes from the DNS stage, it is not part of Total
+type Timing struct {
+	DNS      time.Duration
+	Connect  time.Duration
+	TLS      time.Duration
+	TTFB     time.Duration
+	Download time.Duration
+	Total    time.Duration
+	Reused   bool
+}
+
+// Page is one page reached by the crawl, Hash is computed after normalization
+type Page struct {
+	URL        string
+	Depth      uint
+	StatusCode int
+	Hash       []byte
+}
+
+// Asset is a subresource loaded by the page, fetched through the same client
+type Asset struct {
+	URL         string
+	Kind        string
+	StatusCode  int
+	ContentType string
+	Size        int64
 	Hash        []byte
-	Online      bool
-	Nameserver  Nameserver
-	Filename    string
+	Error       string
+}
+
+// StepResult is a scenario step run from the analyze country, Screenshot and DOM are files in FolderPath
+type StepResult struct {
+	Index      int
+	Action     string
+	Name       string
+	Screenshot string
+	DOM        string
+	DOMHash    []byte
+	Error      string
+}
+
+// VisualDiff compares the screenshot of an analyze with the baseline screenshot of its profile
+type VisualDiff struct {
+	Baseline       string
+	DiffPercent    float64
+	PerceptualHash uint64
+	// Distance is the number of bits differing from the perceptual hash of the baseline
+	Distance     int
+	DiffFilename string
+	Flagged      bool
+}
+
+// Provider is the CDN or hosting provider serving an analyze and what gave it away
+type Provider struct {
+	Name     string
+	Evidence []string
+}
+
+// ComparisonHash is the hash of the normalized body, or of the raw body when no rule applies
+func (a Analyze) ComparisonHash() []byte {
+	if a.NormalizedHash != nil {
+		return a.NormalizedHash
+	}
+	return a.Hash
+}
+
+// UniformNormalization drops every normalized hash when the normalization failed for one of
+// the analyzes, raw and normalized hashes are never compared with each other
+func UniformNormalization(analyzes []Analyze) {
+	failed := false
+	for _, analyze := range analyzes {
+		if analyze.NormalizeError != "" {
+			failed = true
+			break
+		}
+	}
+	if !failed {
+		return
+	}
+
+	log.Printf("Error normalizing some response bodies, raw hashes are compared instead\n")
+	for i := range analyzes {
+		analyzes[i].NormalizedHash = nil
+	}
+}
+
+// IsProbe tells if the analyze is a probe variant, probes are only compared with their regular request
+// by CompareProbes and are left out of the other comparisons and of the browser stages
+func (a Analyze) IsProbe() bool {
+	return a.Probe != ""
 }
 
 // Key Method to convert the struct to a comparable string key
 func (a Analyze) Key() string {
-	return a.IpDest + ":" + strings.Join(a.IpSource, ",") + ":" + a.CountryCode + ":" + string(a.Hash)
+	return a.IpDest + ":" + strings.Join(a.IpSource, ",") + ":" + a.CountryCode + ":" + a.Profile.Name + ":" + a.Protocol + ":" + a.Probe + ":" + string(a.Hash)
 }
 
 func GetAnalyzesByHosts(analyzes []Analyze, countryCode string, hosts []string) []*Analyze {
@@ -112,6 +445,11 @@ func RemoveAnalyzeDuplicates(analyzes []Analyze) []Analyze {
 	return list
 }
 
+// NewHash returns the hash used for bodies, to hash them while they stream
+func NewHash() hash.Hash {
+	return sha3.New256()
+}
+
 func HashByte(body []byte) []byte {
 	hasher := sha3.New256()
 	hasher.Write(body)
@@ -120,13 +458,29 @@ func HashByte(body []byte) []byte {
 	return hashSum
 }
 
+// CompareHash compares the hashes of each profile with the first hash of the same profile
 func CompareHash(analyzes []Analyze) {
-	firstHash := analyzes[0].Hash
+	// probes are only compared with the same probe
+	type variant struct {
+		profile string
+		probe   string
+	}
+	firstHash := make(map[variant][]byte)
 
 	for i := range analyzes {
-		log.Printf("\tip %s: %x\n", analyzes[i].IpDest, analyzes[i].Hash)
-		if !bytes.Equal(analyzes[i].Hash, firstHash) {
-			fmt.Printf("%s has a different hash: %x\n", analyzes[i].IpDest, analyzes[i].Hash)
+		key := variant{analyzes[i].Profile.Name, analyzes[i].Probe}
+		profile := key.profile
+		if key.probe != "" {
+			profile += " " + key.probe
+		}
+		hash := analyzes[i].ComparisonHash()
+		log.Printf("\tip %s (%s): %x\n", analyzes[i].IpDest, profile, hash)
+		if _, ok := firstHash[key]; !ok {
+			firstHash[key] = hash
+			continue
+		}
+		if !bytes.Equal(hash, firstHash[key]) {
+			fmt.Printf("%s has a different hash for profile %s: %x\n", analyzes[i].IpDest, profile, hash)
 
 		}
 	}
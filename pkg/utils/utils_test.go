@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestUniformNormalization(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []Analyze
+		expected [][]byte
+	}{
+		{
+			name: "All normalized",
+			input: []Analyze{
+				{Hash: []byte("raw-fr"), NormalizedHash: []byte("norm")},
+				{Hash: []byte("raw-de"), NormalizedHash: []byte("norm")},
+			},
+			expected: [][]byte{[]byte("norm"), []byte("norm")},
+		},
+		{
+			name: "One failed",
+			input: []Analyze{
+				{Hash: []byte("raw-fr"), NormalizedHash: []byte("norm")},
+				{Hash: []byte("raw-de"), NormalizeError: "rule failed"},
+			},
+			expected: [][]byte{[]byte("raw-fr"), []byte("raw-de")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			UniformNormalization(tt.input)
+			for i, analyze := range tt.input {
+				if got := analyze.ComparisonHash(); !bytes.Equal(got, tt.expected[i]) {
+					t.Errorf("ComparisonHash() of %d got = %s, expected = %s", i, got, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestArtifactName(t *testing.T) {
+	long := strings.Repeat("a", 250) + ".example.com"
+	longProfile := strings.Repeat("p", 250)
+
+	tests := []struct {
+		name     string
+		endpoint EndpointMetadata
+		parts    []string
+		expected string
+	}{
+		{
+			name:     "Short",
+			endpoint: EndpointMetadata{Host: "example.com", Scheme: "https", Port: "443"},
+			parts:    []string{"FR", "2001:db8::1", "default"},
+			expected: "example.com_FR_2001_db8_1_default.png",
+		},
+		{
+			name:     "Port and empty part",
+			endpoint: EndpointMetadata{Host: "example.com", Scheme: "https", Port: "8443"},
+			parts:    []string{"FR", "", "visual-diff"},
+			expected: "example.com_8443_FR_visual-diff.png",
+		},
+		{
+			name:     "Long host keeps the parts",
+			endpoint: EndpointMetadata{Host: long, Scheme: "https", Port: "443"},
+			parts:    []string{"FR", "abcdef"},
+			expected: long[:200-len("_FR_abcdef")] + "_FR_abcdef.png",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.endpoint.ArtifactName("png", tt.parts...); got != tt.expected {
+				t.Errorf("ArtifactName() got = %q, expected = %q", got, tt.expected)
+			}
+		})
+	}
+
+	// parts too long for the host to be kept are hashed, two names differing only at the end stay distinct
+	endpoint := EndpointMetadata{Host: "example.com", Scheme: "https"}
+	a := endpoint.ArtifactName("png", longProfile, "FR")
+	b := endpoint.ArtifactName("png", longProfile, "DE")
+	if a == b || len(a) != maxArtifactName+len(".png") || !strings.HasPrefix(a, "example.com_ppp") {
+		t.Errorf("ArtifactName() got = %q and %q, expected distinct names of %d bytes", a, b, maxArtifactName)
+	}
+}
+
+func TestStatusFromCode(t *testing.T) {
+	tests := []struct {
+		code     int
+		expected string
+	}{
+		{code: 0, expected: StatusUnreachable},
+		{code: 200, expected: StatusOnline},
+		{code: 204, expected: StatusOnline},
+		{code: 301, expected: StatusRedirect},
+		{code: 308, expected: StatusRedirect},
+		{code: 401, expected: StatusBlocked},
+		{code: 403, expected: StatusBlocked},
+		{code: 404, expected: StatusNotFound},
+		{code: 410, expected: StatusNotFound},
+		{code: 418, expected: StatusClientError},
+		{code: 429, expected: StatusRateLimited},
+		{code: 451, expected: StatusLegal},
+		{code: 500, expected: StatusServerError},
+		{code: 503, expected: StatusServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.code), func(t *testing.T) {
+			if got := StatusFromCode(tt.code); got != tt.expected {
+				t.Errorf("StatusFromCode(%d) = %q, expected %q", tt.code, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+// CompareVisual lists the screenshots differing from the baseline of their profile above the visual threshold,
+// the most different first
+func CompareVisual(data []utils.Analyze) []string {
+	var flagged []utils.Analyze
+	for _, entry := range data {
+		if entry.Visual != nil && entry.Visual.Flagged {
+			flagged = append(flagged, entry)
+		}
+	}
+	sort.SliceStable(flagged, func(i, j int) bool {
+		return flagged[i].Visual.DiffPercent > flagged[j].Visual.DiffPercent
+	})
+
+	var notes []string
+	for _, entry := range flagged {
+		notes = append(notes, fmt.Sprintf("%s (%s, %s): %.2f%% of the pixels differ from %s, phash distance %d, see %s",
+			entry.CountryCode, entry.IpDest, entry.Profile.Name, entry.Visual.DiffPercent, entry.Visual.Baseline,
+			entry.Visual.Distance, entry.Visual.DiffFilename))
+	}
+	return notes
+}
+
+func DisplayVisual(notes []string) {
+	if len(notes) == 0 {
+		return
+	}
+
+	fmt.Println("Visual differences:")
+	for _, note := range notes {
+		fmt.Printf("\t%s\n", note)
+	}
+	fmt.Println("")
+}
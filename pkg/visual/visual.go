@@ -0,0 +1,200 @@
+package visual
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log"
+	"math/bits"
+	"os"
+	"path/filepath"
+
+	"github.com/orisano/pixelmatch"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+// per pixel color distance under which two pixels are considered the same
+const pixelThreshold = 0.1
+
+// Align pads both images on a white canvas of the largest width and height, top-left aligned,
+// so that full page screenshots of different heights can be compared
+func Align(a, b image.Image) (*image.RGBA, *image.RGBA) {
+	width := max(a.Bounds().Dx(), b.Bounds().Dx())
+	height := max(a.Bounds().Dy(), b.Bounds().Dy())
+	return pad(a, width, height), pad(b, width, height)
+}
+
+func pad(img image.Image, width, height int) *image.RGBA {
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(canvas, img.Bounds().Sub(img.Bounds().Min), img, img.Bounds().Min, draw.Src)
+	return canvas
+}
+
+// Compare returns the percentage of differing pixels and an image with the differences highlighted
+func Compare(a, b image.Image) (float64, image.Image, error) {
+	alignedA, alignedB := Align(a, b)
+
+	var diff image.Image
+	count, err := pixelmatch.MatchPixel(alignedA, alignedB, pixelmatch.Threshold(pixelThreshold), pixelmatch.WriteTo(&diff))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	total := alignedA.Bounds().Dx() * alignedA.Bounds().Dy()
+	if total == 0 {
+		return 0, diff, nil
+	}
+	return float64(count) * 100 / float64(total), diff, nil
+}
+
+// PerceptualHash is the difference hash of the image: each bit tells if a cell of a 9x8
+// grayscale thumbnail is brighter than its right neighbour
+func PerceptualHash(img image.Image) uint64 {
+	const width, height = 9, 8
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return 0
+	}
+
+	var cells [height][width]float64
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			cells[y][x] = meanLuma(img, image.Rect(
+				bounds.Min.X+x*bounds.Dx()/width, bounds.Min.Y+y*bounds.Dy()/height,
+				bounds.Min.X+(x+1)*bounds.Dx()/width, bounds.Min.Y+(y+1)*bounds.Dy()/height,
+			))
+		}
+	}
+
+	var hash uint64
+	for y := 0; y < height; y++ {
+		for x := 0; x < width-1; x++ {
+			hash <<= 1
+			if cells[y][x] > cells[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// meanLuma averages a sample of at most 16x16 pixels of the cell, enough for a thumbnail
+func meanLuma(img image.Image, cell image.Rectangle) float64 {
+	if cell.Empty() {
+		cell.Max = cell.Min.Add(image.Point{X: 1, Y: 1})
+	}
+	stepX, stepY := max(1, cell.Dx()/16), max(1, cell.Dy()/16)
+
+	var sum float64
+	var n int
+	for y := cell.Min.Y; y < cell.Max.Y; y += stepY {
+		for x := cell.Min.X; x < cell.Max.X; x += stepX {
+			sum += float64(color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y)
+			n++
+		}
+	}
+	return sum / float64(n)
+}
+
+// Distance is the number of differing bits between two perceptual hashes
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// CompareScreenshots compares every screenshot with the one of the baseline variant of its profile and probe,
+// the most frequent comparison hash. Diff images are written in folder, differences above
+// threshold percent are flagged
+func CompareScreenshots(data []utils.Analyze, folder string, resource utils.EndpointMetadata, threshold float64) error {
+	images := make(map[int]image.Image)
+	for i := range data {
+		if data[i].Screenshot == "" {
+			continue
+		}
+		img, err := load(filepath.Join(folder, data[i].Screenshot))
+		if err != nil {
+			// a capture that failed half way is left out of the comparison
+			log.Printf("Error loading screenshot %s: %v\n", data[i].Screenshot, err)
+			continue
+		}
+		images[i] = img
+		data[i].Visual = &utils.VisualDiff{PerceptualHash: PerceptualHash(img)}
+	}
+
+	for i, baseline := range baselines(data, images) {
+		if i == baseline {
+			continue
+		}
+		percent, diff, err := Compare(images[baseline], images[i])
+		if err != nil {
+			return err
+		}
+
+		visual := data[i].Visual
+		visual.Baseline = data[baseline].Screenshot
+		visual.DiffPercent = percent
+		visual.Distance = Distance(visual.PerceptualHash, data[baseline].Visual.PerceptualHash)
+		visual.Flagged = percent > threshold
+		if percent == 0 {
+			continue
+		}
+
+		fileName := resource.ArtifactName("png", data[i].CountryCode, data[i].IpDest, data[i].Profile.Name, data[i].Protocol, data[i].Probe, "visual-diff")
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, diff); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(folder, fileName), buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("failed to save visual diff: %w", err)
+		}
+		visual.DiffFilename = fileName
+	}
+	return nil
+}
+
+// baselines maps each analyze with a screenshot to the analyze of the same profile and probe whose
+// screenshot is the baseline, the first one of the most frequent comparison hash
+func baselines(data []utils.Analyze, images map[int]image.Image) map[int]int {
+	group := func(i int) string {
+		return data[i].Profile.Name + "\x00" + data[i].Probe
+	}
+	variant := func(i int) string {
+		return group(i) + "\x00" + string(data[i].ComparisonHash())
+	}
+
+	count := make(map[string]int)
+	for i := range images {
+		count[variant(i)]++
+	}
+
+	best := make(map[string]int)
+	for i := range data {
+		if _, ok := images[i]; !ok {
+			continue
+		}
+		current, ok := best[group(i)]
+		if !ok || count[variant(i)] > count[variant(current)] {
+			best[group(i)] = i
+		}
+	}
+
+	result := make(map[int]int, len(images))
+	for i := range images {
+		result[i] = best[group(i)]
+	}
+	return result
+}
+
+func load(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return png.Decode(file)
+}
@@ -0,0 +1,121 @@
+package visual
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/OnsagerHe/geoip-detector/pkg/utils"
+)
+
+// page draws a white page of the given height with a dark banner at the top
+func page(height int, banner color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 100, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 0, 100, 20), image.NewUniform(banner), image.Point{}, draw.Src)
+	return img
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     image.Image
+		expected float64
+	}{
+		{
+			name:     "Same page",
+			a:        page(100, color.Black),
+			b:        page(100, color.Black),
+			expected: 0,
+		},
+		{
+			name:     "Different banner",
+			a:        page(100, color.Black),
+			b:        page(100, color.RGBA{R: 255, A: 255}),
+			expected: 20,
+		},
+		{
+			name:     "Longer page padded with white",
+			a:        page(100, color.Black),
+			b:        page(150, color.Black),
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percent, diff, err := Compare(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("Compare() error = %v", err)
+			}
+			if percent != tt.expected {
+				t.Errorf("Compare() = %.2f, expected %.2f", percent, tt.expected)
+			}
+			if percent > 0 && diff == nil {
+				t.Errorf("Compare() returned no diff image")
+			}
+		})
+	}
+}
+
+func TestPerceptualHash(t *testing.T) {
+	a := PerceptualHash(page(100, color.Black))
+	if b := PerceptualHash(page(110, color.Black)); Distance(a, b) > 4 {
+		t.Errorf("PerceptualHash() distance of a slightly longer page = %d", Distance(a, b))
+	}
+
+	inverted := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	draw.Draw(inverted, inverted.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(inverted, image.Rect(50, 0, 100, 100), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	if b := PerceptualHash(inverted); Distance(a, b) == 0 {
+		t.Errorf("PerceptualHash() of a different layout is the same")
+	}
+}
+
+func TestCompareScreenshots(t *testing.T) {
+	folder := t.TempDir()
+	save := func(name string, img image.Image) {
+		file, err := os.Create(filepath.Join(folder, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		if err := png.Encode(file, img); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save("fr.png", page(100, color.Black))
+	save("de.png", page(100, color.Black))
+	save("ru.png", page(100, color.RGBA{R: 255, A: 255}))
+
+	data := []utils.Analyze{
+		{CountryCode: "FR", Hash: []byte("a"), Screenshot: "fr.png"},
+		{CountryCode: "RU", Hash: []byte("b"), Screenshot: "ru.png"},
+		{CountryCode: "DE", Hash: []byte("a"), Screenshot: "de.png"},
+		{CountryCode: "US", Hash: []byte("a")},
+	}
+	resource := utils.EndpointMetadata{Host: "example.com", Scheme: "https", Port: "443"}
+	if err := CompareScreenshots(data, folder, resource, 5); err != nil {
+		t.Fatalf("CompareScreenshots() error = %v", err)
+	}
+
+	if data[0].Visual == nil || data[0].Visual.Baseline != "" {
+		t.Errorf("CompareScreenshots() baseline = %+v, expected no comparison", data[0].Visual)
+	}
+	if data[2].Visual.Baseline != "fr.png" || data[2].Visual.Flagged || data[2].Visual.DiffFilename != "" {
+		t.Errorf("CompareScreenshots() same page = %+v", data[2].Visual)
+	}
+	if !data[1].Visual.Flagged || data[1].Visual.DiffFilename == "" {
+		t.Fatalf("CompareScreenshots() different page = %+v, expected flagged with a diff", data[1].Visual)
+	}
+	if _, err := os.Stat(filepath.Join(folder, data[1].Visual.DiffFilename)); err != nil {
+		t.Errorf("CompareScreenshots() diff not written: %v", err)
+	}
+	if data[3].Visual != nil {
+		t.Errorf("CompareScreenshots() without screenshot = %+v", data[3].Visual)
+	}
+}
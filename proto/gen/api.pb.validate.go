@@ -35,6 +35,120 @@ var (
 	_ = sort.Sort
 )
 
+// Validate checks the field values on RequestProfile with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *RequestProfile) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RequestProfile with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in RequestProfileMultiError,
+// or nil if none found.
+func (m *RequestProfile) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RequestProfile) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Name
+
+	// no validation rules for Method
+
+	// no validation rules for Headers
+
+	// no validation rules for UserAgent
+
+	// no validation rules for AcceptLanguage
+
+	// no validation rules for Cookies
+
+	// no validation rules for Body
+
+	if len(errors) > 0 {
+		return RequestProfileMultiError(errors)
+	}
+
+	return nil
+}
+
+// RequestProfileMultiError is an error wrapping multiple validation errors
+// returned by RequestProfile.ValidateAll() if the designated constraints
+// aren't met.
+type RequestProfileMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RequestProfileMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RequestProfileMultiError) AllErrors() []error { return m }
+
+// RequestProfileValidationError is the validation error returned by
+// RequestProfile.Validate if the designated constraints aren't met.
+type RequestProfileValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RequestProfileValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RequestProfileValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RequestProfileValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RequestProfileValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RequestProfileValidationError) ErrorName() string { return "RequestProfileValidationError" }
+
+// Error satisfies the builtin error interface
+func (e RequestProfileValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRequestProfile.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RequestProfileValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RequestProfileValidationError{}
+
 // Validate checks the field values on PutEndpointRequest with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the first error encountered is returned, or nil if there are no violations.
@@ -61,6 +175,40 @@ func (m *PutEndpointRequest) validate(all bool) error {
 
 	// no validation rules for Loop
 
+	for idx, item := range m.GetProfiles() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, PutEndpointRequestValidationError{
+						field:  fmt.Sprintf("Profiles[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, PutEndpointRequestValidationError{
+						field:  fmt.Sprintf("Profiles[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return PutEndpointRequestValidationError{
+					field:  fmt.Sprintf("Profiles[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
 	if len(errors) > 0 {
 		return PutEndpointRequestMultiError(errors)
 	}
@@ -141,22 +289,22 @@ var _ interface {
 	ErrorName() string
 } = PutEndpointRequestValidationError{}
 
-// Validate checks the field values on PutEndpointResponse with the rules
-// defined in the proto definition for this message. If any rules are
-// violated, the first error encountered is returned, or nil if there are no violations.
-func (m *PutEndpointResponse) Validate() error {
+// Validate checks the field values on MetadataEndpoint with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *MetadataEndpoint) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on PutEndpointResponse with the rules
+// ValidateAll checks the field values on MetadataEndpoint with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// PutEndpointResponseMultiError, or nil if none found.
-func (m *PutEndpointResponse) ValidateAll() error {
+// MetadataEndpointMultiError, or nil if none found.
+func (m *MetadataEndpoint) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *PutEndpointResponse) validate(all bool) error {
+func (m *MetadataEndpoint) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
@@ -169,22 +317,301 @@ func (m *PutEndpointResponse) validate(all bool) error {
 
 	// no validation rules for HashFile
 
-	// no validation rules for Filname
+	// no validation rules for Filename
+
+	// no validation rules for StatusCode
+
+	// no validation rules for Headers
+
+	// no validation rules for ContentType
+
+	// no validation rules for BodySize
+
+	// no validation rules for CountryCode
+
+	// no validation rules for Profile
+
+	// no validation rules for NormalizedHash
+
+	// no validation rules for Diff
+
+	// no validation rules for DiffFilename
+
+	if all {
+		switch v := interface{}(m.GetTiming()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, MetadataEndpointValidationError{
+					field:  "Timing",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, MetadataEndpointValidationError{
+					field:  "Timing",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetTiming()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return MetadataEndpointValidationError{
+				field:  "Timing",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Protocol
+
+	// no validation rules for NegotiatedProtocol
+
+	for idx, item := range m.GetPages() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, MetadataEndpointValidationError{
+						field:  fmt.Sprintf("Pages[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, MetadataEndpointValidationError{
+						field:  fmt.Sprintf("Pages[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return MetadataEndpointValidationError{
+					field:  fmt.Sprintf("Pages[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetAssets() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, MetadataEndpointValidationError{
+						field:  fmt.Sprintf("Assets[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, MetadataEndpointValidationError{
+						field:  fmt.Sprintf("Assets[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return MetadataEndpointValidationError{
+					field:  fmt.Sprintf("Assets[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if all {
+		switch v := interface{}(m.GetVerdict()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, MetadataEndpointValidationError{
+					field:  "Verdict",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, MetadataEndpointValidationError{
+					field:  "Verdict",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetVerdict()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return MetadataEndpointValidationError{
+				field:  "Verdict",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Attempts
+
+	// no validation rules for Error
+
+	// no validation rules for Truncated
+
+	// no validation rules for Probe
+
+	for idx, item := range m.GetSteps() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, MetadataEndpointValidationError{
+						field:  fmt.Sprintf("Steps[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, MetadataEndpointValidationError{
+						field:  fmt.Sprintf("Steps[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return MetadataEndpointValidationError{
+					field:  fmt.Sprintf("Steps[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if all {
+		switch v := interface{}(m.GetVisual()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, MetadataEndpointValidationError{
+					field:  "Visual",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, MetadataEndpointValidationError{
+					field:  "Visual",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetVisual()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return MetadataEndpointValidationError{
+				field:  "Visual",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetEmulation()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, MetadataEndpointValidationError{
+					field:  "Emulation",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, MetadataEndpointValidationError{
+					field:  "Emulation",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetEmulation()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return MetadataEndpointValidationError{
+				field:  "Emulation",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetTls()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, MetadataEndpointValidationError{
+					field:  "Tls",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, MetadataEndpointValidationError{
+					field:  "Tls",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetTls()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return MetadataEndpointValidationError{
+				field:  "Tls",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for NormalizeError
 
 	if len(errors) > 0 {
-		return PutEndpointResponseMultiError(errors)
+		return MetadataEndpointMultiError(errors)
 	}
 
 	return nil
 }
 
-// PutEndpointResponseMultiError is an error wrapping multiple validation
-// errors returned by PutEndpointResponse.ValidateAll() if the designated
-// constraints aren't met.
-type PutEndpointResponseMultiError []error
+// MetadataEndpointMultiError is an error wrapping multiple validation errors
+// returned by MetadataEndpoint.ValidateAll() if the designated constraints
+// aren't met.
+type MetadataEndpointMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m PutEndpointResponseMultiError) Error() string {
+func (m MetadataEndpointMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -193,11 +620,11 @@ func (m PutEndpointResponseMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m PutEndpointResponseMultiError) AllErrors() []error { return m }
+func (m MetadataEndpointMultiError) AllErrors() []error { return m }
 
-// PutEndpointResponseValidationError is the validation error returned by
-// PutEndpointResponse.Validate if the designated constraints aren't met.
-type PutEndpointResponseValidationError struct {
+// MetadataEndpointValidationError is the validation error returned by
+// MetadataEndpoint.Validate if the designated constraints aren't met.
+type MetadataEndpointValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -205,24 +632,133 @@ type PutEndpointResponseValidationError struct {
 }
 
 // Field function returns field value.
-func (e PutEndpointResponseValidationError) Field() string { return e.field }
+func (e MetadataEndpointValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e PutEndpointResponseValidationError) Reason() string { return e.reason }
+func (e MetadataEndpointValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e PutEndpointResponseValidationError) Cause() error { return e.cause }
+func (e MetadataEndpointValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e PutEndpointResponseValidationError) Key() bool { return e.key }
+func (e MetadataEndpointValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e PutEndpointResponseValidationError) ErrorName() string {
-	return "PutEndpointResponseValidationError"
+func (e MetadataEndpointValidationError) ErrorName() string { return "MetadataEndpointValidationError" }
+
+// Error satisfies the builtin error interface
+func (e MetadataEndpointValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sMetadataEndpoint.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = MetadataEndpointValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = MetadataEndpointValidationError{}
+
+// Validate checks the field values on Certificate with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Certificate) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Certificate with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in CertificateMultiError, or
+// nil if none found.
+func (m *Certificate) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Certificate) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Subject
+
+	// no validation rules for Issuer
+
+	// no validation rules for Serial
+
+	// no validation rules for NotBefore
+
+	// no validation rules for NotAfter
+
+	// no validation rules for SpkiHash
+
+	if len(errors) > 0 {
+		return CertificateMultiError(errors)
+	}
+
+	return nil
+}
+
+// CertificateMultiError is an error wrapping multiple validation errors
+// returned by Certificate.ValidateAll() if the designated constraints aren't met.
+type CertificateMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CertificateMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CertificateMultiError) AllErrors() []error { return m }
+
+// CertificateValidationError is the validation error returned by
+// Certificate.Validate if the designated constraints aren't met.
+type CertificateValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
 }
 
+// Field function returns field value.
+func (e CertificateValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CertificateValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CertificateValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CertificateValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CertificateValidationError) ErrorName() string { return "CertificateValidationError" }
+
 // Error satisfies the builtin error interface
-func (e PutEndpointResponseValidationError) Error() string {
+func (e CertificateValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -234,14 +770,14 @@ func (e PutEndpointResponseValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sPutEndpointResponse.%s: %s%s",
+		"invalid %sCertificate.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = PutEndpointResponseValidationError{}
+var _ error = CertificateValidationError{}
 
 var _ interface {
 	Field() string
@@ -249,4 +785,1467 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = PutEndpointResponseValidationError{}
+} = CertificateValidationError{}
+
+// Validate checks the field values on TLSInfo with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *TLSInfo) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on TLSInfo with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in TLSInfoMultiError, or nil if none found.
+func (m *TLSInfo) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *TLSInfo) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Version
+
+	// no validation rules for CipherSuite
+
+	// no validation rules for Alpn
+
+	// no validation rules for ServerName
+
+	for idx, item := range m.GetChain() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, TLSInfoValidationError{
+						field:  fmt.Sprintf("Chain[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, TLSInfoValidationError{
+						field:  fmt.Sprintf("Chain[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return TLSInfoValidationError{
+					field:  fmt.Sprintf("Chain[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for OcspStatus
+
+	// no validation rules for VerifyError
+
+	if len(errors) > 0 {
+		return TLSInfoMultiError(errors)
+	}
+
+	return nil
+}
+
+// TLSInfoMultiError is an error wrapping multiple validation errors returned
+// by TLSInfo.ValidateAll() if the designated constraints aren't met.
+type TLSInfoMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TLSInfoMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TLSInfoMultiError) AllErrors() []error { return m }
+
+// TLSInfoValidationError is the validation error returned by TLSInfo.Validate
+// if the designated constraints aren't met.
+type TLSInfoValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TLSInfoValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TLSInfoValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TLSInfoValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TLSInfoValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TLSInfoValidationError) ErrorName() string { return "TLSInfoValidationError" }
+
+// Error satisfies the builtin error interface
+func (e TLSInfoValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTLSInfo.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TLSInfoValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TLSInfoValidationError{}
+
+// Validate checks the field values on Emulation with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Emulation) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Emulation with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in EmulationMultiError, or nil
+// if none found.
+func (m *Emulation) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Emulation) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Timezone
+
+	// no validation rules for TimezoneCountry
+
+	// no validation rules for Locale
+
+	// no validation rules for AcceptLanguage
+
+	// no validation rules for LocaleCountry
+
+	// no validation rules for Latitude
+
+	// no validation rules for Longitude
+
+	// no validation rules for GeolocationCountry
+
+	if len(errors) > 0 {
+		return EmulationMultiError(errors)
+	}
+
+	return nil
+}
+
+// EmulationMultiError is an error wrapping multiple validation errors returned
+// by Emulation.ValidateAll() if the designated constraints aren't met.
+type EmulationMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m EmulationMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m EmulationMultiError) AllErrors() []error { return m }
+
+// EmulationValidationError is the validation error returned by
+// Emulation.Validate if the designated constraints aren't met.
+type EmulationValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e EmulationValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e EmulationValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e EmulationValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e EmulationValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e EmulationValidationError) ErrorName() string { return "EmulationValidationError" }
+
+// Error satisfies the builtin error interface
+func (e EmulationValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sEmulation.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = EmulationValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = EmulationValidationError{}
+
+// Validate checks the field values on Visual with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Visual) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Visual with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in VisualMultiError, or nil if none found.
+func (m *Visual) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Visual) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Baseline
+
+	// no validation rules for DiffPercent
+
+	// no validation rules for PerceptualHash
+
+	// no validation rules for Distance
+
+	// no validation rules for DiffFilename
+
+	// no validation rules for Flagged
+
+	if len(errors) > 0 {
+		return VisualMultiError(errors)
+	}
+
+	return nil
+}
+
+// VisualMultiError is an error wrapping multiple validation errors returned by
+// Visual.ValidateAll() if the designated constraints aren't met.
+type VisualMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m VisualMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m VisualMultiError) AllErrors() []error { return m }
+
+// VisualValidationError is the validation error returned by Visual.Validate if
+// the designated constraints aren't met.
+type VisualValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e VisualValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e VisualValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e VisualValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e VisualValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e VisualValidationError) ErrorName() string { return "VisualValidationError" }
+
+// Error satisfies the builtin error interface
+func (e VisualValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sVisual.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = VisualValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = VisualValidationError{}
+
+// Validate checks the field values on ScenarioStep with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *ScenarioStep) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ScenarioStep with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in ScenarioStepMultiError, or
+// nil if none found.
+func (m *ScenarioStep) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ScenarioStep) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Index
+
+	// no validation rules for Action
+
+	// no validation rules for Name
+
+	// no validation rules for Screenshot
+
+	// no validation rules for Dom
+
+	// no validation rules for DomHash
+
+	// no validation rules for Error
+
+	if len(errors) > 0 {
+		return ScenarioStepMultiError(errors)
+	}
+
+	return nil
+}
+
+// ScenarioStepMultiError is an error wrapping multiple validation errors
+// returned by ScenarioStep.ValidateAll() if the designated constraints aren't met.
+type ScenarioStepMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ScenarioStepMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ScenarioStepMultiError) AllErrors() []error { return m }
+
+// ScenarioStepValidationError is the validation error returned by
+// ScenarioStep.Validate if the designated constraints aren't met.
+type ScenarioStepValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ScenarioStepValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ScenarioStepValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ScenarioStepValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ScenarioStepValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ScenarioStepValidationError) ErrorName() string { return "ScenarioStepValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ScenarioStepValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sScenarioStep.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ScenarioStepValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ScenarioStepValidationError{}
+
+// Validate checks the field values on Verdict with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Verdict) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Verdict with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in VerdictMultiError, or nil if none found.
+func (m *Verdict) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Verdict) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Label
+
+	// no validation rules for Signature
+
+	// no validation rules for Detail
+
+	if len(errors) > 0 {
+		return VerdictMultiError(errors)
+	}
+
+	return nil
+}
+
+// VerdictMultiError is an error wrapping multiple validation errors returned
+// by Verdict.ValidateAll() if the designated constraints aren't met.
+type VerdictMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m VerdictMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m VerdictMultiError) AllErrors() []error { return m }
+
+// VerdictValidationError is the validation error returned by Verdict.Validate
+// if the designated constraints aren't met.
+type VerdictValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e VerdictValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e VerdictValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e VerdictValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e VerdictValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e VerdictValidationError) ErrorName() string { return "VerdictValidationError" }
+
+// Error satisfies the builtin error interface
+func (e VerdictValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sVerdict.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = VerdictValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = VerdictValidationError{}
+
+// Validate checks the field values on Asset with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Asset) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Asset with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in AssetMultiError, or nil if none found.
+func (m *Asset) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Asset) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Url
+
+	// no validation rules for Kind
+
+	// no validation rules for StatusCode
+
+	// no validation rules for ContentType
+
+	// no validation rules for Size
+
+	// no validation rules for Hash
+
+	// no validation rules for Error
+
+	if len(errors) > 0 {
+		return AssetMultiError(errors)
+	}
+
+	return nil
+}
+
+// AssetMultiError is an error wrapping multiple validation errors returned by
+// Asset.ValidateAll() if the designated constraints aren't met.
+type AssetMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AssetMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AssetMultiError) AllErrors() []error { return m }
+
+// AssetValidationError is the validation error returned by Asset.Validate if
+// the designated constraints aren't met.
+type AssetValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AssetValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AssetValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AssetValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AssetValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AssetValidationError) ErrorName() string { return "AssetValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AssetValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAsset.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AssetValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AssetValidationError{}
+
+// Validate checks the field values on CrawledPage with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *CrawledPage) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CrawledPage with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in CrawledPageMultiError, or
+// nil if none found.
+func (m *CrawledPage) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CrawledPage) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Url
+
+	// no validation rules for Depth
+
+	// no validation rules for StatusCode
+
+	// no validation rules for Hash
+
+	if len(errors) > 0 {
+		return CrawledPageMultiError(errors)
+	}
+
+	return nil
+}
+
+// CrawledPageMultiError is an error wrapping multiple validation errors
+// returned by CrawledPage.ValidateAll() if the designated constraints aren't met.
+type CrawledPageMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CrawledPageMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CrawledPageMultiError) AllErrors() []error { return m }
+
+// CrawledPageValidationError is the validation error returned by
+// CrawledPage.Validate if the designated constraints aren't met.
+type CrawledPageValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CrawledPageValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CrawledPageValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CrawledPageValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CrawledPageValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CrawledPageValidationError) ErrorName() string { return "CrawledPageValidationError" }
+
+// Error satisfies the builtin error interface
+func (e CrawledPageValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCrawledPage.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CrawledPageValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CrawledPageValidationError{}
+
+// Validate checks the field values on Timing with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Timing) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Timing with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in TimingMultiError, or nil if none found.
+func (m *Timing) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Timing) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for DnsMs
+
+	// no validation rules for ConnectMs
+
+	// no validation rules for TlsMs
+
+	// no validation rules for TtfbMs
+
+	// no validation rules for DownloadMs
+
+	// no validation rules for TotalMs
+
+	if len(errors) > 0 {
+		return TimingMultiError(errors)
+	}
+
+	return nil
+}
+
+// TimingMultiError is an error wrapping multiple validation errors returned by
+// Timing.ValidateAll() if the designated constraints aren't met.
+type TimingMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TimingMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TimingMultiError) AllErrors() []error { return m }
+
+// TimingValidationError is the validation error returned by Timing.Validate if
+// the designated constraints aren't met.
+type TimingValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TimingValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TimingValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TimingValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TimingValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TimingValidationError) ErrorName() string { return "TimingValidationError" }
+
+// Error satisfies the builtin error interface
+func (e TimingValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTiming.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TimingValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TimingValidationError{}
+
+// Validate checks the field values on PutEndpointResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *PutEndpointResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on PutEndpointResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// PutEndpointResponseMultiError, or nil if none found.
+func (m *PutEndpointResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *PutEndpointResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetMetadata() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, PutEndpointResponseValidationError{
+						field:  fmt.Sprintf("Metadata[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, PutEndpointResponseValidationError{
+						field:  fmt.Sprintf("Metadata[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return PutEndpointResponseValidationError{
+					field:  fmt.Sprintf("Metadata[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetSimilarity() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, PutEndpointResponseValidationError{
+						field:  fmt.Sprintf("Similarity[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, PutEndpointResponseValidationError{
+						field:  fmt.Sprintf("Similarity[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return PutEndpointResponseValidationError{
+					field:  fmt.Sprintf("Similarity[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return PutEndpointResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// PutEndpointResponseMultiError is an error wrapping multiple validation
+// errors returned by PutEndpointResponse.ValidateAll() if the designated
+// constraints aren't met.
+type PutEndpointResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m PutEndpointResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m PutEndpointResponseMultiError) AllErrors() []error { return m }
+
+// PutEndpointResponseValidationError is the validation error returned by
+// PutEndpointResponse.Validate if the designated constraints aren't met.
+type PutEndpointResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e PutEndpointResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e PutEndpointResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e PutEndpointResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e PutEndpointResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e PutEndpointResponseValidationError) ErrorName() string {
+	return "PutEndpointResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e PutEndpointResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sPutEndpointResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = PutEndpointResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = PutEndpointResponseValidationError{}
+
+// Validate checks the field values on SimilarityRow with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *SimilarityRow) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SimilarityRow with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in SimilarityRowMultiError, or
+// nil if none found.
+func (m *SimilarityRow) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SimilarityRow) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return SimilarityRowMultiError(errors)
+	}
+
+	return nil
+}
+
+// SimilarityRowMultiError is an error wrapping multiple validation errors
+// returned by SimilarityRow.ValidateAll() if the designated constraints
+// aren't met.
+type SimilarityRowMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SimilarityRowMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SimilarityRowMultiError) AllErrors() []error { return m }
+
+// SimilarityRowValidationError is the validation error returned by
+// SimilarityRow.Validate if the designated constraints aren't met.
+type SimilarityRowValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SimilarityRowValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SimilarityRowValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SimilarityRowValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SimilarityRowValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SimilarityRowValidationError) ErrorName() string { return "SimilarityRowValidationError" }
+
+// Error satisfies the builtin error interface
+func (e SimilarityRowValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSimilarityRow.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SimilarityRowValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SimilarityRowValidationError{}
+
+// Validate checks the field values on SimilarityPair with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *SimilarityPair) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SimilarityPair with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in SimilarityPairMultiError,
+// or nil if none found.
+func (m *SimilarityPair) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SimilarityPair) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for CountryA
+
+	// no validation rules for CountryB
+
+	// no validation rules for Verdict
+
+	if len(errors) > 0 {
+		return SimilarityPairMultiError(errors)
+	}
+
+	return nil
+}
+
+// SimilarityPairMultiError is an error wrapping multiple validation errors
+// returned by SimilarityPair.ValidateAll() if the designated constraints
+// aren't met.
+type SimilarityPairMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SimilarityPairMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SimilarityPairMultiError) AllErrors() []error { return m }
+
+// SimilarityPairValidationError is the validation error returned by
+// SimilarityPair.Validate if the designated constraints aren't met.
+type SimilarityPairValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SimilarityPairValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SimilarityPairValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SimilarityPairValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SimilarityPairValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SimilarityPairValidationError) ErrorName() string { return "SimilarityPairValidationError" }
+
+// Error satisfies the builtin error interface
+func (e SimilarityPairValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSimilarityPair.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SimilarityPairValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SimilarityPairValidationError{}
+
+// Validate checks the field values on SimilarityMatrix with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *SimilarityMatrix) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SimilarityMatrix with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SimilarityMatrixMultiError, or nil if none found.
+func (m *SimilarityMatrix) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SimilarityMatrix) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Profile
+
+	// no validation rules for Threshold
+
+	for idx, item := range m.GetRows() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, SimilarityMatrixValidationError{
+						field:  fmt.Sprintf("Rows[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, SimilarityMatrixValidationError{
+						field:  fmt.Sprintf("Rows[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return SimilarityMatrixValidationError{
+					field:  fmt.Sprintf("Rows[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetPairs() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, SimilarityMatrixValidationError{
+						field:  fmt.Sprintf("Pairs[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, SimilarityMatrixValidationError{
+						field:  fmt.Sprintf("Pairs[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return SimilarityMatrixValidationError{
+					field:  fmt.Sprintf("Pairs[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return SimilarityMatrixMultiError(errors)
+	}
+
+	return nil
+}
+
+// SimilarityMatrixMultiError is an error wrapping multiple validation errors
+// returned by SimilarityMatrix.ValidateAll() if the designated constraints
+// aren't met.
+type SimilarityMatrixMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SimilarityMatrixMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SimilarityMatrixMultiError) AllErrors() []error { return m }
+
+// SimilarityMatrixValidationError is the validation error returned by
+// SimilarityMatrix.Validate if the designated constraints aren't met.
+type SimilarityMatrixValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SimilarityMatrixValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SimilarityMatrixValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SimilarityMatrixValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SimilarityMatrixValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SimilarityMatrixValidationError) ErrorName() string { return "SimilarityMatrixValidationError" }
+
+// Error satisfies the builtin error interface
+func (e SimilarityMatrixValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSimilarityMatrix.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SimilarityMatrixValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SimilarityMatrixValidationError{}